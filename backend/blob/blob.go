@@ -0,0 +1,139 @@
+// Package blob abstracts where uploaded files are stored (local disk or an
+// S3-compatible object store). Keys are slash-separated paths relative to the
+// public /files/ prefix, e.g. "images/<sha256>.jpg".
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned when a key does not exist.
+var ErrNotFound = errors.New("blob not found")
+
+// Info describes a stored object.
+type Info struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+	ETag        string // quoted entity tag as sent in HTTP headers, e.g. "\"abc\""; may be empty
+}
+
+// Store is implemented by every storage backend.
+type Store interface {
+	// Put writes data under key atomically; readers never see partial objects.
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get opens key for reading. The reader is an io.ReadSeeker when the
+	// backend supports it (local disk).
+	Get(ctx context.Context, key string) (io.ReadCloser, Info, error)
+	Stat(ctx context.Context, key string) (Info, error)
+	// Delete removes key; deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// List returns all objects whose key starts with prefix.
+	List(ctx context.Context, prefix string) ([]Info, error)
+}
+
+// Presigner is implemented by backends that can hand out temporary direct URLs.
+type Presigner interface {
+	PresignGet(key string, ttl time.Duration) (string, error)
+}
+
+var (
+	defaultMu       sync.Mutex
+	defaultOverride Store
+	defaultStore    Store
+)
+
+// ErrNotConfigured is returned by the default store before Init has run.
+var ErrNotConfigured = errors.New("blob store not configured; call blob.Init")
+
+// Init builds the process-wide store with FromEnv. Call it once at startup
+// (tests call it after pointing UPLOAD_DIR at a temp dir); the environment is
+// not consulted again afterwards.
+func Init() error {
+	s, err := FromEnv()
+	if err != nil {
+		return err
+	}
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultStore = s
+	return nil
+}
+
+// Default returns the store set by SetDefault or Init. Before either has
+// run it returns a store whose every call fails with ErrNotConfigured.
+func Default() Store {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	switch {
+	case defaultOverride != nil:
+		return defaultOverride
+	case defaultStore != nil:
+		return defaultStore
+	}
+	return unconfigured{}
+}
+
+// SetDefault overrides the process-wide store (tests, custom wiring).
+// Passing nil restores the store built by Init.
+func SetDefault(s Store) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultOverride = s
+}
+
+type unconfigured struct{}
+
+func (unconfigured) Put(context.Context, string, []byte, string) error { return ErrNotConfigured }
+func (unconfigured) Get(context.Context, string) (io.ReadCloser, Info, error) {
+	return nil, Info{}, ErrNotConfigured
+}
+func (unconfigured) Stat(context.Context, string) (Info, error)   { return Info{}, ErrNotConfigured }
+func (unconfigured) Delete(context.Context, string) error         { return ErrNotConfigured }
+func (unconfigured) List(context.Context, string) ([]Info, error) { return nil, ErrNotConfigured }
+
+// IsLocal reports whether BLOB_BACKEND selects local disk storage.
+func IsLocal() bool {
+	b := strings.ToLower(os.Getenv("BLOB_BACKEND"))
+	return b == "" || b == "local"
+}
+
+// FromEnv builds a store from BLOB_BACKEND ("local", the default, or "s3").
+// local uses UPLOAD_DIR; s3 uses the S3_* variables (see NewS3FromEnv).
+func FromEnv() (Store, error) {
+	switch strings.ToLower(os.Getenv("BLOB_BACKEND")) {
+	case "", "local":
+		dir := os.Getenv("UPLOAD_DIR")
+		if dir == "" {
+			return nil, errors.New("UPLOAD_DIR environment variable must be set")
+		}
+		return NewLocal(dir), nil
+	case "s3":
+		return NewS3FromEnv()
+	default:
+		return nil, fmt.Errorf("unknown BLOB_BACKEND %q", os.Getenv("BLOB_BACKEND"))
+	}
+}
+
+// ValidKey reports whether key is a clean relative path without traversal.
+// Segments starting with "." (e.g. Local's ".upload-*" temp files) are never
+// valid, so they cannot be served or addressed.
+func ValidKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.HasSuffix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || strings.HasPrefix(part, ".") {
+			return false
+		}
+	}
+	return true
+}
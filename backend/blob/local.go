@@ -0,0 +1,181 @@
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Local stores blobs as files under a root directory.
+type Local struct {
+	root string
+}
+
+// NewLocal returns a disk-backed store rooted at dir.
+func NewLocal(dir string) *Local {
+	return &Local{root: dir}
+}
+
+// Root returns the directory blobs are stored under.
+func (l *Local) Root() string { return l.root }
+
+func (l *Local) path(key string) (string, error) {
+	if !ValidKey(key) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(l.root, filepath.FromSlash(key)), nil
+}
+
+// Put writes via a temp file and rename.
+func (l *Local) Put(_ context.Context, key string, data []byte, _ string) (err error) {
+	dst, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(dst)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+// Get opens the file; the returned reader is an *os.File (seekable).
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, Info, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, Info{}, ErrNotFound
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, Info{}, ErrNotFound
+		}
+		return nil, Info{}, err
+	}
+	st, err := f.Stat()
+	if err != nil || !st.Mode().IsRegular() {
+		f.Close()
+		return nil, Info{}, ErrNotFound
+	}
+	return f, l.info(key, st), nil
+}
+
+func (l *Local) Stat(_ context.Context, key string) (Info, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return Info{}, ErrNotFound
+	}
+	st, err := os.Stat(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Info{}, ErrNotFound
+		}
+		return Info{}, err
+	}
+	if !st.Mode().IsRegular() {
+		return Info{}, ErrNotFound
+	}
+	return l.info(key, st), nil
+}
+
+func (l *Local) Delete(_ context.Context, key string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// List walks the root; hidden files (temp files, probes) are skipped.
+func (l *Local) List(_ context.Context, prefix string) ([]Info, error) {
+	var out []Info
+	err := filepath.WalkDir(l.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		rel, err := filepath.Rel(l.root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		st, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		out = append(out, l.info(key, st))
+		return nil
+	})
+	return out, err
+}
+
+func (l *Local) info(key string, st os.FileInfo) Info {
+	return Info{
+		Key:         key,
+		Size:        st.Size(),
+		ContentType: contentTypeByExt(path.Ext(key)),
+		ModTime:     st.ModTime(),
+	}
+}
+
+// knownTypes covers the formats the app stores, independent of the host's
+// mime.types (which may be missing or map .webm to video/webm).
+var knownTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".mp3":  "audio/mpeg",
+	".wav":  "audio/wav",
+	".ogg":  "audio/ogg",
+	".flac": "audio/flac",
+	".webm": "audio/webm",
+	".m4a":  "audio/mp4",
+}
+
+func contentTypeByExt(ext string) string {
+	if ct, ok := knownTypes[strings.ToLower(ext)]; ok {
+		return ct
+	}
+	return mime.TypeByExtension(ext)
+}
@@ -0,0 +1,72 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestLocal_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	l := NewLocal(t.TempDir())
+
+	if err := l.Put(ctx, "images/cat.png", []byte("meow"), "image/png"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	rc, info, err := l.Get(ctx, "images/cat.png")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "meow" || info.Size != 4 || info.ContentType != "image/png" {
+		t.Fatalf("Get = %q %+v", data, info)
+	}
+	if _, ok := rc.(io.ReadSeeker); !ok {
+		t.Error("expected local reader to be seekable")
+	}
+
+	list, err := l.List(ctx, "images/")
+	if err != nil || len(list) != 1 || list[0].Key != "images/cat.png" {
+		t.Fatalf("List = %+v, %v", list, err)
+	}
+
+	if err := l.Delete(ctx, "images/cat.png"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := l.Stat(ctx, "images/cat.png"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Stat after delete: %v", err)
+	}
+	if err := l.Delete(ctx, "images/cat.png"); err != nil {
+		t.Fatalf("Delete missing: %v", err)
+	}
+}
+
+func TestLocal_RejectsTraversal(t *testing.T) {
+	l := NewLocal(t.TempDir())
+	if err := l.Put(context.Background(), "../escape.txt", []byte("x"), ""); err == nil {
+		t.Fatal("expected error for traversal key")
+	}
+	if _, err := l.Stat(context.Background(), "../escape.txt"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestValidKey(t *testing.T) {
+	for key, want := range map[string]bool{
+		"images/cat.png":       true,
+		"images/w160/cat.png":  true,
+		"../escape.txt":        false,
+		"images/./cat.png":     false,
+		"images/.upload-12345": false,
+		".hidden/cat.png":      false,
+		"/images/cat.png":      false,
+		"images/":              false,
+		`images\cat.png`:       false,
+	} {
+		if got := ValidKey(key); got != want {
+			t.Errorf("ValidKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
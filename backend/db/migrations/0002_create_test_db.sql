@@ -4,6 +4,24 @@
 -- Create test database owned by the same user
 CREATE DATABASE learnlang_test;
 
--- Connect to the test database and apply the initial schema/seeds
+-- Connect to the test database and apply the schema/seeds
 \connect learnlang_test
 \i /docker-entrypoint-initdb.d/0001_init.sql
+\i /docker-entrypoint-initdb.d/0003_public_catalogue.sql
+\i /docker-entrypoint-initdb.d/0004_vocab_search.sql
+\i /docker-entrypoint-initdb.d/0005_vocab_links.sql
+\i /docker-entrypoint-initdb.d/0006_media.sql
+\i /docker-entrypoint-initdb.d/0007_vocab_audio.sql
+\i /docker-entrypoint-initdb.d/0008_tts_audio.sql
+\i /docker-entrypoint-initdb.d/0009_vocab_images.sql
+\i /docker-entrypoint-initdb.d/0010_vocab_details.sql
+\i /docker-entrypoint-initdb.d/0011_vocab_grammar.sql
+\i /docker-entrypoint-initdb.d/0012_vocab_transliteration.sql
+\i /docker-entrypoint-initdb.d/0013_vocab_translations.sql
+\i /docker-entrypoint-initdb.d/0014_vocab_name_norm.sql
+\i /docker-entrypoint-initdb.d/0015_language_metadata.sql
+\i /docker-entrypoint-initdb.d/0016_pack_source_language.sql
+\i /docker-entrypoint-initdb.d/0017_vocab_sorting.sql
+\i /docker-entrypoint-initdb.d/0018_translation_cache.sql
+\i /docker-entrypoint-initdb.d/0019_dictionary.sql
+\i /docker-entrypoint-initdb.d/0020_media_refs.sql
@@ -0,0 +1,16 @@
+-- Public pack catalogue: owner display names, creation time and fork lineage
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS users (
+  id   TEXT PRIMARY KEY,
+  name TEXT NOT NULL
+);
+
+ALTER TABLE packs ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE packs ADD COLUMN IF NOT EXISTS forked_from TEXT REFERENCES packs(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS packs_public_lang_idx ON packs (lang_id) WHERE public;
+CREATE INDEX IF NOT EXISTS packs_forked_from_idx ON packs (forked_from);
+
+COMMIT;
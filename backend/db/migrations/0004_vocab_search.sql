@@ -0,0 +1,17 @@
+-- Full-text and fuzzy vocab search
+
+BEGIN;
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() is only STABLE; wrap it so it can be used in index expressions.
+CREATE OR REPLACE FUNCTION f_unaccent(text) RETURNS text
+  LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+  AS $$ SELECT public.unaccent('public.unaccent'::regdictionary, $1) $$;
+
+CREATE INDEX IF NOT EXISTS vocabs_name_trgm_idx ON vocabs USING gin (f_unaccent(lower(name)) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS vocabs_translation_trgm_idx ON vocabs USING gin (f_unaccent(lower(translation)) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS vocabs_fts_idx ON vocabs USING gin (to_tsvector('simple', f_unaccent(name || ' ' || translation)));
+
+COMMIT;
@@ -0,0 +1,13 @@
+-- Links between duplicate vocabs across a user's packs
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS vocab_links (
+  vocab_id     TEXT PRIMARY KEY REFERENCES vocabs(id) ON DELETE CASCADE,
+  canonical_id TEXT NOT NULL REFERENCES vocabs(id) ON DELETE CASCADE,
+  CONSTRAINT vocab_links_not_self CHECK (vocab_id <> canonical_id)
+);
+
+CREATE INDEX IF NOT EXISTS vocab_links_canonical_idx ON vocab_links (canonical_id);
+
+COMMIT;
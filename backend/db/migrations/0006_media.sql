@@ -0,0 +1,18 @@
+-- Content-addressed media blobs referenced by vocabs.image
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS media (
+  hash         TEXT PRIMARY KEY,          -- hex SHA-256 of the stored bytes
+  path         TEXT NOT NULL UNIQUE,      -- public URL path, e.g. /files/images/<hash>.jpg
+  content_type TEXT NOT NULL,
+  size_bytes   BIGINT NOT NULL,
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+  -- bumped whenever the same bytes are uploaded again; the garbage
+  -- collector's grace period counts from here, not from the file's mtime
+  last_uploaded_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS vocabs_image_idx ON vocabs (image);
+
+COMMIT;
@@ -0,0 +1,15 @@
+-- Pronunciation clips attached to vocabs (files live under /files/audio/)
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS vocab_audio (
+  vocab_id   TEXT NOT NULL REFERENCES vocabs(id) ON DELETE CASCADE,
+  path       TEXT NOT NULL,                -- public URL path, e.g. /files/audio/<hash>.mp3
+  position   INT  NOT NULL DEFAULT 0,      -- display order within the vocab
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (vocab_id, path)
+);
+
+CREATE INDEX IF NOT EXISTS vocab_audio_path_idx ON vocab_audio (path);
+
+COMMIT;
@@ -0,0 +1,13 @@
+-- Cache of synthesized pronunciations, keyed by language code and exact text
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS tts_audio (
+  lang_code  TEXT NOT NULL,
+  text       TEXT NOT NULL,
+  path       TEXT NOT NULL,                -- public URL path, e.g. /files/audio/<sha256>.wav
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (lang_code, text)
+);
+
+COMMIT;
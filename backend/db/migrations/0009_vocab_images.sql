@@ -0,0 +1,43 @@
+-- Ordered image lists per vocab. vocabs.image stays the primary image and is
+-- always part of the list; the trigger keeps both in sync for every writer.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS vocab_images (
+  vocab_id   TEXT NOT NULL REFERENCES vocabs(id) ON DELETE CASCADE,
+  path       TEXT NOT NULL,                -- public URL path, e.g. /files/images/<hash>.jpg
+  position   INT  NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (vocab_id, path)
+);
+
+CREATE INDEX IF NOT EXISTS vocab_images_path_idx ON vocab_images (path);
+
+INSERT INTO vocab_images (vocab_id, path, position)
+  SELECT id, image, 0 FROM vocabs WHERE image <> ''
+  ON CONFLICT DO NOTHING;
+
+-- New primary images join the list. Replacing the primary with an image that
+-- is not listed yet swaps it in place; choosing a listed image as primary
+-- leaves the list alone.
+CREATE OR REPLACE FUNCTION vocab_images_sync() RETURNS trigger AS $$
+BEGIN
+  IF NEW.image = '' THEN
+    RETURN NEW;
+  END IF;
+  IF TG_OP = 'UPDATE' AND NEW.image IS DISTINCT FROM OLD.image
+     AND NOT EXISTS (SELECT 1 FROM vocab_images WHERE vocab_id = NEW.id AND path = NEW.image) THEN
+    UPDATE vocab_images SET path = NEW.image WHERE vocab_id = NEW.id AND path = OLD.image;
+  END IF;
+  INSERT INTO vocab_images (vocab_id, path, position)
+    VALUES (NEW.id, NEW.image, COALESCE((SELECT max(position) + 1 FROM vocab_images WHERE vocab_id = NEW.id), 0))
+    ON CONFLICT DO NOTHING;
+  RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS vocab_images_sync ON vocabs;
+CREATE TRIGGER vocab_images_sync AFTER INSERT OR UPDATE OF image ON vocabs
+  FOR EACH ROW EXECUTE FUNCTION vocab_images_sync();
+
+COMMIT;
@@ -0,0 +1,26 @@
+-- Optional rich vocab fields: example sentences, notes, part of speech, tags
+
+BEGIN;
+
+ALTER TABLE vocabs
+  ADD COLUMN IF NOT EXISTS notes          TEXT  NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS part_of_speech TEXT  NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS tags           JSONB NOT NULL DEFAULT '[]',  -- ["kitchen", "a1"]
+  ADD COLUMN IF NOT EXISTS examples       JSONB NOT NULL DEFAULT '[]';  -- [{"text": ..., "translation": ...}]
+
+CREATE INDEX IF NOT EXISTS vocabs_tags_idx ON vocabs USING gin (tags jsonb_path_ops);
+
+-- Searchable text of the rich fields. Declared IMMUTABLE (jsonb text output
+-- does not depend on settings) so it can be indexed.
+CREATE OR REPLACE FUNCTION vocab_details_text(notes text, tags jsonb, examples jsonb) RETURNS text
+  LANGUAGE sql IMMUTABLE PARALLEL SAFE
+  AS $$
+    SELECT concat_ws(' ', notes,
+      (SELECT string_agg(t, ' ') FROM jsonb_array_elements_text(tags) AS t),
+      (SELECT string_agg(concat_ws(' ', e->>'text', e->>'translation'), ' ') FROM jsonb_array_elements(examples) AS e))
+  $$;
+
+CREATE INDEX IF NOT EXISTS vocabs_details_fts_idx ON vocabs
+  USING gin (to_tsvector('simple', f_unaccent(lower(vocab_details_text(notes, tags, examples)))));
+
+COMMIT;
@@ -0,0 +1,13 @@
+-- Language-specific grammatical attributes of a vocab (gender, plural, verb
+-- principal parts, ...), validated per language by the API.
+
+BEGIN;
+
+ALTER TABLE vocabs
+  ADD COLUMN IF NOT EXISTS grammar JSONB NOT NULL DEFAULT '{}';  -- {"gender": "neuter", "plural": "Messer"}
+
+-- Article quiz picks nouns with a known gender.
+CREATE INDEX IF NOT EXISTS vocabs_grammar_gender_idx ON vocabs ((grammar->>'gender'))
+  WHERE grammar ? 'gender';
+
+COMMIT;
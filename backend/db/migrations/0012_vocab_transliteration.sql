@@ -0,0 +1,9 @@
+-- Romanised spelling of a vocab's Devanagari text (Hindi), generated by the
+-- API with a rule-based IAST/ITRANS scheme and editable by users.
+
+BEGIN;
+
+ALTER TABLE vocabs
+  ADD COLUMN IF NOT EXISTS transliteration TEXT NOT NULL DEFAULT '';
+
+COMMIT;
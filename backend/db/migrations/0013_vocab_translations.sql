@@ -0,0 +1,20 @@
+-- Accepted translations besides the primary vocabs.translation, and synonyms
+-- of the vocab name. Both are used for answer checking and search.
+
+BEGIN;
+
+ALTER TABLE vocabs
+  ADD COLUMN IF NOT EXISTS alternate_translations JSONB NOT NULL DEFAULT '[]',  -- ["automobile"] next to translation "car"
+  ADD COLUMN IF NOT EXISTS synonyms               JSONB NOT NULL DEFAULT '[]';  -- ["Wagen"] next to name "Auto"
+
+-- Searchable text of both lists, IMMUTABLE so it can be indexed.
+CREATE OR REPLACE FUNCTION vocab_alternates_text(alternate_translations jsonb, synonyms jsonb) RETURNS text
+  LANGUAGE sql IMMUTABLE PARALLEL SAFE
+  AS $$
+    SELECT coalesce(string_agg(t, ' '), '') FROM jsonb_array_elements_text(alternate_translations || synonyms) AS t
+  $$;
+
+CREATE INDEX IF NOT EXISTS vocabs_alternates_trgm_idx ON vocabs
+  USING gin (f_unaccent(lower(vocab_alternates_text(alternate_translations, synonyms))) gin_trgm_ops);
+
+COMMIT;
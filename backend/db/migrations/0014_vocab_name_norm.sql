@@ -0,0 +1,21 @@
+-- Folded vocab name (NFC, case-folded, per-language rules such as ß -> ss or
+-- Hindi nukta removal; see package textnorm). Written by the API on every
+-- vocab write; rows from before this migration keep '' until
+-- `go run . backfill-names` fills them in.
+--
+-- Per-pack uniqueness moves from the raw name to the folded one. Rows still
+-- holding '' are left out of the index, so it can be created before the
+-- backfill; the backfill reports rows whose folded name is already taken
+-- instead of writing it, so they can be merged first.
+
+BEGIN;
+
+ALTER TABLE vocabs
+  ADD COLUMN IF NOT EXISTS name_norm TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE vocabs DROP CONSTRAINT IF EXISTS vocabs_unique_per_pack_name;
+
+DROP INDEX IF EXISTS vocabs_pack_name_norm_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS vocabs_pack_name_norm_key ON vocabs (pack_id, name_norm) WHERE name_norm <> '';
+
+COMMIT;
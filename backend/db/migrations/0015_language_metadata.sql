@@ -0,0 +1,17 @@
+-- Language metadata for the admin API: native name, script (ISO 15924), text
+-- direction, collation locale (BCP 47) and a soft-disable flag. Packs keep
+-- referencing disabled languages; new packs cannot use them.
+
+BEGIN;
+
+ALTER TABLE languages
+  ADD COLUMN IF NOT EXISTS native_name TEXT    NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS script      TEXT    NOT NULL DEFAULT '',     -- e.g. Deva, Latn, Arab
+  ADD COLUMN IF NOT EXISTS direction   TEXT    NOT NULL DEFAULT 'ltr' CHECK (direction IN ('ltr', 'rtl')),
+  ADD COLUMN IF NOT EXISTS collation   TEXT    NOT NULL DEFAULT '',     -- e.g. de-DE; empty means the code
+  ADD COLUMN IF NOT EXISTS enabled     BOOLEAN NOT NULL DEFAULT TRUE;
+
+UPDATE languages SET native_name = 'हिन्दी', script = 'Deva', collation = 'hi' WHERE code = 'hi' AND native_name = '';
+UPDATE languages SET native_name = 'Deutsch', script = 'Latn', collation = 'de' WHERE code = 'de' AND native_name = '';
+
+COMMIT;
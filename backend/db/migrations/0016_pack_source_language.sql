@@ -0,0 +1,26 @@
+-- Source/target language pairs: packs.lang_id is the language being learned,
+-- packs.source_lang_id the language of the translations. Translations used to
+-- be implicitly English, so English is seeded and existing packs point to it.
+-- A user may have packs of the same name for different source languages.
+-- languages.seeded marks the rows created by migrations (hi, de, en).
+
+BEGIN;
+
+INSERT INTO languages (id, name, code, native_name, script, collation) VALUES
+  ('3', 'English', 'en', 'English', 'Latn', 'en')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE packs ADD COLUMN IF NOT EXISTS source_lang_id TEXT REFERENCES languages(id) ON DELETE RESTRICT;
+UPDATE packs SET source_lang_id = (SELECT id FROM languages WHERE code = 'en') WHERE source_lang_id IS NULL;
+ALTER TABLE packs ALTER COLUMN source_lang_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS packs_lang_pair_idx ON packs (lang_id, source_lang_id);
+
+ALTER TABLE packs DROP CONSTRAINT IF EXISTS packs_unique_per_user_lang_name;
+ALTER TABLE packs DROP CONSTRAINT IF EXISTS packs_unique_per_user_lang_pair_name;
+ALTER TABLE packs ADD CONSTRAINT packs_unique_per_user_lang_pair_name UNIQUE (user_id, lang_id, source_lang_id, name);
+
+ALTER TABLE languages ADD COLUMN IF NOT EXISTS seeded BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE languages SET seeded = TRUE WHERE code IN ('hi', 'de', 'en');
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- Sort keys for vocab lists: creation time and a user-rated difficulty
+-- (1 easy .. 5 hard, 0 unrated). Name and translation are sorted in Go with
+-- the collation of the pack's languages (languages.collation).
+
+BEGIN;
+
+ALTER TABLE vocabs ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE vocabs ADD COLUMN IF NOT EXISTS difficulty SMALLINT NOT NULL DEFAULT 0 CHECK (difficulty BETWEEN 0 AND 5);
+
+COMMIT;
@@ -0,0 +1,20 @@
+-- Cache of machine-translation suggestions, keyed by the translator that
+-- produced them, the language pair and the source text as typed (NFC,
+-- trimmed; case is kept since it can change the translation, e.g. Arm/arm).
+-- Rows older than TRANSLATE_CACHE_TTL are pruned when new ones are saved.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS translation_cache (
+  provider    TEXT NOT NULL,               -- translate.Translator.Name()
+  from_code   TEXT NOT NULL,
+  to_code     TEXT NOT NULL,
+  text        TEXT NOT NULL,
+  suggestions JSONB NOT NULL DEFAULT '[]',  -- best first
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (provider, from_code, to_code, text)
+);
+
+CREATE INDEX IF NOT EXISTS translation_cache_created_idx ON translation_cache (created_at);
+
+COMMIT;
@@ -0,0 +1,23 @@
+-- Offline bilingual dictionary imported from local dumps (FreeDict TEI,
+-- Wiktionary-derived TSV) with `go run . dict-import`. Headwords are in
+-- lang_id, definitions in source_lang_id (as for packs); headword_norm is the
+-- headword folded with textnorm.Fold for prefix lookups.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS dictionary_entries (
+  id             BIGSERIAL PRIMARY KEY,
+  lang_id        TEXT  NOT NULL REFERENCES languages(id) ON DELETE CASCADE,
+  source_lang_id TEXT  NOT NULL REFERENCES languages(id) ON DELETE CASCADE,
+  headword       TEXT  NOT NULL,
+  headword_norm  TEXT  NOT NULL,
+  part_of_speech TEXT  NOT NULL DEFAULT '',
+  gender         TEXT  NOT NULL DEFAULT '',
+  definitions    JSONB NOT NULL DEFAULT '[]',
+  source         TEXT  NOT NULL DEFAULT '',   -- dump the entry came from, e.g. freedict-deu-eng
+  CONSTRAINT dictionary_entries_unique UNIQUE (lang_id, source_lang_id, headword_norm, part_of_speech)
+);
+
+CREATE INDEX IF NOT EXISTS dictionary_entries_prefix_idx ON dictionary_entries (lang_id, headword_norm text_pattern_ops);
+
+COMMIT;
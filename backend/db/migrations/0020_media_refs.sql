@@ -0,0 +1,26 @@
+-- Every reference that keeps a stored blob alive, one row per referencing row.
+-- store.MediaRefCount and store.ReferencedFiles (used by the garbage collector)
+-- read this view, so it is the single definition of a live blob.
+-- vocabs.image is always mirrored into vocab_images by its trigger.
+-- Synthesized pronunciations only count while some vocab still has that name
+-- in that language; stale tts_audio rows are pruned by the garbage collector.
+
+BEGIN;
+
+DROP VIEW IF EXISTS media_refs;
+CREATE VIEW media_refs AS
+  SELECT path FROM vocab_images
+  UNION ALL
+  SELECT path FROM vocab_audio
+  UNION ALL
+  SELECT t.path FROM tts_audio t
+  WHERE EXISTS (
+    SELECT 1 FROM vocabs v
+    JOIN packs p ON p.id = v.pack_id
+    JOIN languages l ON l.id = p.lang_id
+    WHERE l.code = t.lang_code AND v.name = t.text
+  );
+
+CREATE INDEX IF NOT EXISTS tts_audio_path_idx ON tts_audio (path);
+
+COMMIT;
@@ -0,0 +1,153 @@
+// Package dictionary imports local bilingual dictionary dumps (FreeDict TEI
+// XML or Wiktionary-derived TSV) into the dictionary_entries table, which
+// backs word lookups while adding vocab. It runs as the `dict-import`
+// subcommand.
+package dictionary
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"learnlang-backend/models"
+	"learnlang-backend/store"
+	"learnlang-backend/utils"
+)
+
+// Format is the layout of a dictionary dump.
+type Format string
+
+const (
+	// FormatTSV: headword, part of speech, gender, then one or more
+	// definitions, tab separated; blank lines and lines starting with # are
+	// ignored.
+	FormatTSV Format = "tsv"
+	// FormatXML: FreeDict TEI (<entry> elements with form/orth, gramGrp and
+	// sense/cit[@type=trans]/quote or sense/def).
+	FormatXML Format = "xml"
+)
+
+// batchSize is the number of entries upserted per transaction.
+const batchSize = 500
+
+// maxDefinitions caps definitions kept per entry; dumps sometimes list
+// dozens of near-synonyms.
+const maxDefinitions = 10
+
+// FormatFromPath infers the format from a file name, e.g. deu-eng.tei → xml.
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tsv", ".txt":
+		return FormatTSV, nil
+	case ".xml", ".tei":
+		return FormatXML, nil
+	}
+	return "", fmt.Errorf("cannot infer dictionary format from %q; pass -format tsv|xml", path)
+}
+
+// Options controls an import.
+type Options struct {
+	LangID       string // language of the headwords
+	SourceLangID string // language of the definitions
+	Source       string // recorded on each entry, e.g. freedict-deu-eng
+}
+
+// Report summarizes an import.
+type Report struct {
+	Read     int `json:"read"`     // entries found in the dump
+	Imported int `json:"imported"` // entries inserted or merged
+	Skipped  int `json:"skipped"`  // entries without headword or definitions
+}
+
+// Import reads a dump and upserts its entries. Re-importing the same or an
+// overlapping dump merges definitions instead of duplicating entries.
+func Import(ctx context.Context, r io.Reader, format Format, opts Options) (Report, error) {
+	var rep Report
+	lang, ok := store.GetLanguageByID(opts.LangID)
+	if !ok {
+		return rep, fmt.Errorf("unknown language %q", opts.LangID)
+	}
+	if _, ok := store.GetLanguageByID(opts.SourceLangID); !ok {
+		return rep, fmt.Errorf("unknown source language %q", opts.SourceLangID)
+	}
+	if opts.LangID == opts.SourceLangID {
+		return rep, fmt.Errorf("language and source language must differ")
+	}
+
+	batch := make([]models.DictionaryEntry, 0, batchSize)
+	flush := func() error {
+		n, err := store.UpsertDictionaryEntries(batch)
+		rep.Imported += n
+		batch = batch[:0]
+		return err
+	}
+	err := Parse(r, format, func(e models.DictionaryEntry) error {
+		rep.Read++
+		if !normalize(&e, lang.Code) {
+			rep.Skipped++
+			return nil
+		}
+		e.LangID, e.SourceLangID, e.Source = opts.LangID, opts.SourceLangID, opts.Source
+		batch = append(batch, e)
+		if len(batch) < batchSize {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return flush()
+	})
+	if err == nil {
+		err = flush()
+	}
+	return rep, err
+}
+
+// posAliases maps abbreviations used by FreeDict and Wiktionary dumps to
+// utils.PartsOfSpeech.
+var posAliases = map[string]string{
+	"n": "noun", "nn": "noun", "noun": "noun", "proper noun": "noun", "name": "noun",
+	"v": "verb", "vi": "verb", "vt": "verb", "vb": "verb", "verb": "verb",
+	"adj": "adjective", "a": "adjective", "adv": "adverb",
+	"pron": "pronoun", "prep": "preposition", "postp": "postposition",
+	"conj": "conjunction", "int": "interjection", "interj": "interjection", "intj": "interjection",
+	"num": "numeral", "art": "article", "det": "article", "ptcl": "particle", "part": "particle",
+	"phr": "phrase", "phrase": "phrase",
+}
+
+// normalize trims an entry in place, maps its part of speech and gender to
+// the values vocab accepts (dropping ones it does not know) and dedupes its
+// definitions. It reports false if the entry is unusable.
+func normalize(e *models.DictionaryEntry, langCode string) bool {
+	e.Headword = strings.TrimSpace(e.Headword)
+	pos := strings.ToLower(strings.Trim(strings.TrimSpace(e.PartOfSpeech), "."))
+	if alias, ok := posAliases[pos]; ok {
+		pos = alias
+	}
+	e.PartOfSpeech = ""
+	for _, p := range utils.PartsOfSpeech {
+		if pos == p {
+			e.PartOfSpeech = p
+		}
+	}
+	gender, ok := utils.CanonicalGender(langCode, strings.Trim(e.Gender, "."))
+	if !ok {
+		gender = ""
+	}
+	e.Gender = gender
+
+	seen := map[string]bool{}
+	defs := e.Definitions[:0]
+	for _, d := range e.Definitions {
+		d = strings.Join(strings.Fields(d), " ")
+		if d == "" || seen[strings.ToLower(d)] || len(defs) == maxDefinitions {
+			continue
+		}
+		seen[strings.ToLower(d)] = true
+		defs = append(defs, d)
+	}
+	e.Definitions = defs
+	return e.Headword != "" && len(defs) > 0
+}
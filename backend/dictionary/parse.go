@@ -0,0 +1,127 @@
+package dictionary
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"learnlang-backend/models"
+)
+
+// Parse streams the raw entries of a dump to emit, stopping at the first
+// error emit returns. Entries are not normalized.
+func Parse(r io.Reader, format Format, emit func(models.DictionaryEntry) error) error {
+	switch format {
+	case FormatTSV:
+		return parseTSV(r, emit)
+	case FormatXML:
+		return parseTEI(r, emit)
+	}
+	return fmt.Errorf("unsupported dictionary format %q", format)
+}
+
+func parseTSV(r io.Reader, emit func(models.DictionaryEntry) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		f := strings.Split(line, "\t")
+		e := models.DictionaryEntry{Headword: f[0]}
+		if len(f) > 1 {
+			e.PartOfSpeech = f[1]
+		}
+		if len(f) > 2 {
+			e.Gender = f[2]
+		}
+		if len(f) > 3 {
+			e.Definitions = f[3:]
+		}
+		if err := emit(e); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// teiEntry covers the FreeDict TEI P5 layout and the older P4 <trans><tr>.
+type teiEntry struct {
+	Orth    []string   `xml:"form>orth"`
+	Gram    teiGramGrp `xml:"gramGrp"`
+	Senses  []teiSense `xml:"sense"`
+	TransP4 []string   `xml:"trans>tr"`
+}
+
+type teiGramGrp struct {
+	Pos []string `xml:"pos"`
+	Gen []string `xml:"gen"`
+}
+
+type teiSense struct {
+	Gram  teiGramGrp `xml:"gramGrp"`
+	Cits  []teiCit   `xml:"cit"`
+	Defs  []string   `xml:"def"`
+	Trans []string   `xml:"trans>tr"`
+}
+
+type teiCit struct {
+	Type   string   `xml:"type,attr"`
+	Quotes []string `xml:"quote"`
+}
+
+func parseTEI(r io.Reader, emit func(models.DictionaryEntry) error) error {
+	dec := xml.NewDecoder(r)
+	dec.Strict = false // FreeDict files reference DTD entities
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "entry" {
+			continue
+		}
+		var te teiEntry
+		if err := dec.DecodeElement(&te, &start); err != nil {
+			return err
+		}
+		if err := emit(te.entry()); err != nil {
+			return err
+		}
+	}
+}
+
+func (te teiEntry) entry() models.DictionaryEntry {
+	e := models.DictionaryEntry{Definitions: te.TransP4}
+	if len(te.Orth) > 0 {
+		e.Headword = te.Orth[0]
+	}
+	gram := te.Gram
+	for _, s := range te.Senses {
+		gram.Pos = append(gram.Pos, s.Gram.Pos...)
+		gram.Gen = append(gram.Gen, s.Gram.Gen...)
+		for _, c := range s.Cits {
+			if c.Type == "trans" || c.Type == "translation" {
+				e.Definitions = append(e.Definitions, c.Quotes...)
+			}
+		}
+		e.Definitions = append(e.Definitions, s.Defs...)
+		e.Definitions = append(e.Definitions, s.Trans...)
+	}
+	if len(gram.Pos) > 0 {
+		e.PartOfSpeech = gram.Pos[0]
+	}
+	if len(gram.Gen) > 0 {
+		e.Gender = gram.Gen[0]
+	}
+	return e
+}
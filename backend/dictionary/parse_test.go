@@ -0,0 +1,97 @@
+package dictionary
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"learnlang-backend/models"
+)
+
+func parseAll(t *testing.T, src string, format Format) []models.DictionaryEntry {
+	t.Helper()
+	var out []models.DictionaryEntry
+	if err := Parse(strings.NewReader(src), format, func(e models.DictionaryEntry) error {
+		out = append(out, e)
+		return nil
+	}); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	return out
+}
+
+func TestParseTSV(t *testing.T) {
+	src := "# headword\tpos\tgender\tdefinitions\n" +
+		"Messer\tn\tn\tknife\tblade\r\n" +
+		"\n" +
+		"laufen\tv\t\tto run\n" +
+		"ohne\n"
+	got := parseAll(t, src, FormatTSV)
+	want := []models.DictionaryEntry{
+		{Headword: "Messer", PartOfSpeech: "n", Gender: "n", Definitions: []string{"knife", "blade"}},
+		{Headword: "laufen", PartOfSpeech: "v", Definitions: []string{"to run"}},
+		{Headword: "ohne"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestParseTEI(t *testing.T) {
+	src := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE TEI SYSTEM "freedict-P5.dtd">
+<TEI xmlns="http://www.tei-c.org/ns/1.0"><text><body>
+<entry>
+  <form><orth>Messer</orth><pron>ˈmɛsɐ</pron></form>
+  <gramGrp><pos>n</pos><gen>neut</gen></gramGrp>
+  <sense n="1"><cit type="trans"><quote>knife</quote></cit><cit type="trans"><quote>blade</quote></cit></sense>
+  <sense n="2"><def>cutting tool&nbsp;(kitchen)</def></sense>
+</entry>
+<entry>
+  <form><orth>Zeitung</orth></form>
+  <sense><gramGrp><pos>n</pos><gen>f</gen></gramGrp><cit type="trans"><quote>newspaper</quote></cit></sense>
+</entry>
+<entry><form><orth>laufen</orth></form><gramGrp><pos>v</pos></gramGrp><trans><tr>to run</tr></trans></entry>
+</body></text></TEI>`
+	got := parseAll(t, src, FormatXML)
+	want := []models.DictionaryEntry{
+		{Headword: "Messer", PartOfSpeech: "n", Gender: "neut", Definitions: []string{"knife", "blade", "cutting tool\u00a0(kitchen)"}},
+		{Headword: "Zeitung", PartOfSpeech: "n", Gender: "f", Definitions: []string{"newspaper"}},
+		{Headword: "laufen", PartOfSpeech: "v", Definitions: []string{"to run"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	e := models.DictionaryEntry{Headword: " Messer ", PartOfSpeech: "n.", Gender: "das", Definitions: []string{"knife", " Knife", "", "a  blade"}}
+	if !normalize(&e, "de") {
+		t.Fatal("expected entry to be kept")
+	}
+	want := models.DictionaryEntry{Headword: "Messer", PartOfSpeech: "noun", Gender: "neuter", Definitions: []string{"knife", "a blade"}}
+	if !reflect.DeepEqual(e, want) {
+		t.Fatalf("got %+v, want %+v", e, want)
+	}
+
+	// unknown part of speech and genders the language lacks are dropped
+	e = models.DictionaryEntry{Headword: "बोलना", PartOfSpeech: "idiom", Gender: "neuter", Definitions: []string{"to speak"}}
+	if !normalize(&e, "hi") || e.PartOfSpeech != "" || e.Gender != "" {
+		t.Fatalf("unexpected %+v", e)
+	}
+	e = models.DictionaryEntry{Headword: "ohne", Definitions: []string{" "}}
+	if normalize(&e, "de") {
+		t.Fatal("entry without definitions should be skipped")
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	for path, want := range map[string]Format{"deu-eng.tei": FormatXML, "hin-eng.XML": FormatXML, "de-en.tsv": FormatTSV} {
+		if got, err := FormatFromPath(path); err != nil || got != want {
+			t.Errorf("FormatFromPath(%q) = %q, %v; want %q", path, got, err, want)
+		}
+	}
+	if _, err := FormatFromPath("dump.json"); err == nil {
+		t.Error("expected error for unknown extension")
+	}
+}
@@ -0,0 +1,251 @@
+// Package gc removes uploaded images and audio clips that no vocab references
+// any more, e.g. images replaced by UpdateVocabHandler or saved before a create
+// failed validation. It runs as the `gc` subcommand or periodically in the server.
+package gc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"learnlang-backend/blob"
+	"learnlang-backend/store"
+)
+
+// DefaultGrace protects fresh uploads whose vocab row is not written yet.
+const DefaultGrace = 24 * time.Hour
+
+// Options controls a collection run.
+type Options struct {
+	Grace  time.Duration // only blobs older than this are collected
+	DryRun bool          // report orphans without touching them
+	// QuarantineDir, when set, receives a copy of each orphan (at its blob key)
+	// before it is deleted from the store. Must be outside UPLOAD_DIR.
+	QuarantineDir string
+	// StillReferenced is consulted right before removing an orphan, closing
+	// the window between the reference snapshot and the delete. Optional.
+	StillReferenced func(publicURL string) bool
+	// LastUploaded returns when an orphan's bytes were last uploaded. A
+	// re-upload of an old blob restarts its grace period even though the
+	// stored object (and its ModTime) is left untouched. Optional.
+	LastUploaded func(publicURL string) time.Time
+	Now          func() time.Time
+}
+
+// Validate rejects a QuarantineDir inside UPLOAD_DIR, where quarantined
+// files would stay servable under /files/ and be scanned again.
+func (o Options) Validate() error {
+	if o.QuarantineDir == "" {
+		return nil
+	}
+	uploads := os.Getenv("UPLOAD_DIR")
+	if uploads == "" {
+		return nil
+	}
+	q, err := filepath.Abs(o.QuarantineDir)
+	if err != nil {
+		return err
+	}
+	u, err := filepath.Abs(uploads)
+	if err != nil {
+		return err
+	}
+	if rel, err := filepath.Rel(u, q); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("quarantine dir %q must be outside UPLOAD_DIR %q", o.QuarantineDir, uploads)
+	}
+	return nil
+}
+
+// Orphan is an unreferenced image (or variant of one) or audio clip.
+type Orphan struct {
+	URL     string    `json:"url"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Removed bool      `json:"removed"`
+}
+
+// Report summarises a run.
+type Report struct {
+	DryRun     bool     `json:"dry_run"`
+	Scanned    int      `json:"scanned"`
+	Referenced int      `json:"referenced"`
+	Young      int      `json:"young"`     // orphans still inside the grace period
+	StaleTTS   int64    `json:"stale_tts"` // cached pronunciations no vocab uses any more
+	Orphans    []Orphan `json:"orphans"`
+	Removed    int      `json:"removed"`
+	Bytes      int64    `json:"bytes"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+// Run collects orphans in the default blob store using vocab references from the database.
+func Run(ctx context.Context, opts Options) (Report, error) {
+	if err := opts.Validate(); err != nil {
+		return Report{}, err
+	}
+	var stale int64
+	if !opts.DryRun {
+		n, err := store.PruneTTSAudio()
+		if err != nil {
+			return Report{}, fmt.Errorf("prune tts cache: %w", err)
+		}
+		stale = n
+	}
+	refs, err := store.ReferencedFiles()
+	if err != nil {
+		return Report{}, fmt.Errorf("load file references: %w", err)
+	}
+	if opts.StillReferenced == nil {
+		opts.StillReferenced = func(u string) bool { return store.MediaRefCount(u) > 0 }
+	}
+	if opts.LastUploaded == nil {
+		opts.LastUploaded = store.MediaLastUploaded
+	}
+	rep, err := Sweep(ctx, blob.Default(), refs, opts)
+	rep.StaleTTS = stale
+	if err != nil || opts.DryRun {
+		return rep, err
+	}
+	for _, o := range rep.Orphans {
+		if !o.Removed {
+			continue
+		}
+		if err := store.DeleteMedia(o.URL); err != nil {
+			rep.Errors = append(rep.Errors, fmt.Sprintf("forget %s: %v", o.URL, err))
+		}
+	}
+	return rep, nil
+}
+
+// Sweep scans images/ and audio/ in st and removes blobs whose public URL is
+// not in referenced. Variants (images/w<width>/<name>) follow their full image.
+func Sweep(ctx context.Context, st blob.Store, referenced map[string]bool, opts Options) (Report, error) {
+	now := time.Now
+	if opts.Now != nil {
+		now = opts.Now
+	}
+	cutoff := now().Add(-opts.Grace)
+	rep := Report{DryRun: opts.DryRun, Orphans: []Orphan{}}
+
+	var objs []blob.Info
+	for _, prefix := range []string{"images/", "audio/"} {
+		infos, err := st.List(ctx, prefix)
+		if err != nil {
+			return rep, fmt.Errorf("list %s: %w", prefix, err)
+		}
+		objs = append(objs, infos...)
+	}
+	sort.Slice(objs, func(i, j int) bool { return objs[i].Key < objs[j].Key })
+	for _, o := range objs {
+		rep.Scanned++
+		url := "/files/" + baseKey(o.Key)
+		if referenced[url] {
+			rep.Referenced++
+			continue
+		}
+		uploaded := o.ModTime
+		if opts.LastUploaded != nil {
+			if t := opts.LastUploaded(url); t.After(uploaded) {
+				uploaded = t
+			}
+		}
+		if uploaded.After(cutoff) {
+			rep.Young++
+			continue
+		}
+		if opts.StillReferenced != nil && opts.StillReferenced(url) {
+			rep.Referenced++
+			continue
+		}
+		orphan := Orphan{URL: "/files/" + o.Key, Size: o.Size, ModTime: o.ModTime}
+		rep.Bytes += o.Size
+		if opts.DryRun {
+			rep.Orphans = append(rep.Orphans, orphan)
+			continue
+		}
+		if opts.QuarantineDir != "" {
+			if err := quarantine(ctx, st, o.Key, opts.QuarantineDir); err != nil {
+				rep.Errors = append(rep.Errors, fmt.Sprintf("quarantine %s: %v", o.Key, err))
+				rep.Orphans = append(rep.Orphans, orphan)
+				continue
+			}
+		}
+		if err := st.Delete(ctx, o.Key); err != nil {
+			rep.Errors = append(rep.Errors, fmt.Sprintf("delete %s: %v", o.Key, err))
+			rep.Orphans = append(rep.Orphans, orphan)
+			continue
+		}
+		orphan.Removed = true
+		rep.Orphans = append(rep.Orphans, orphan)
+		rep.Removed++
+	}
+	return rep, nil
+}
+
+// baseKey maps a variant key to the key of its full image.
+func baseKey(key string) string {
+	rest, ok := strings.CutPrefix(key, "images/")
+	if !ok {
+		return key
+	}
+	if dir, name, ok := strings.Cut(rest, "/"); ok && strings.HasPrefix(dir, "w") && !strings.Contains(name, "/") {
+		return "images/" + name
+	}
+	return key
+}
+
+func quarantine(ctx context.Context, st blob.Store, key, dir string) error {
+	rc, _, err := st.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	dst := filepath.Join(dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(dst), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, rc); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// StartBackground runs Run every interval until ctx is cancelled.
+func StartBackground(ctx context.Context, interval time.Duration, opts Options) {
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				rep, err := Run(ctx, opts)
+				if err != nil {
+					log.Printf("image gc: %v", err)
+					continue
+				}
+				log.Printf("image gc: scanned=%d orphans=%d removed=%d bytes=%d errors=%d", rep.Scanned, len(rep.Orphans), rep.Removed, rep.Bytes, len(rep.Errors))
+			}
+		}
+	}()
+}
+
+// OptionsFromEnv reads GC_GRACE_PERIOD (Go duration) and GC_QUARANTINE_DIR.
+func OptionsFromEnv() Options {
+	opts := Options{Grace: DefaultGrace, QuarantineDir: os.Getenv("GC_QUARANTINE_DIR")}
+	if d, err := time.ParseDuration(os.Getenv("GC_GRACE_PERIOD")); err == nil && d >= 0 {
+		opts.Grace = d
+	}
+	return opts
+}
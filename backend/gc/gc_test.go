@@ -0,0 +1,122 @@
+package gc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"learnlang-backend/blob"
+)
+
+func TestSweep(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	st := blob.NewLocal(dir)
+	for _, key := range []string{"images/kept.png", "images/w160/kept.png", "images/old.png", "images/w160/old.png", "images/new.png"} {
+		if err := st.Put(ctx, key, []byte("x"), "image/png"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	for _, key := range []string{"images/kept.png", "images/w160/kept.png", "images/old.png", "images/w160/old.png"} {
+		if err := os.Chtimes(filepath.Join(dir, filepath.FromSlash(key)), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	refs := map[string]bool{"/files/images/kept.png": true}
+	opts := Options{Grace: 24 * time.Hour, DryRun: true}
+
+	rep, err := Sweep(ctx, st, refs, opts)
+	if err != nil {
+		t.Fatalf("Sweep: %v", err)
+	}
+	if rep.Scanned != 5 || rep.Referenced != 2 || rep.Young != 1 || len(rep.Orphans) != 2 || rep.Removed != 0 {
+		t.Fatalf("dry run report: %+v", rep)
+	}
+	if _, err := st.Stat(ctx, "images/old.png"); err != nil {
+		t.Fatalf("dry run must not delete: %v", err)
+	}
+
+	quarantine := t.TempDir()
+	opts = Options{Grace: 24 * time.Hour, QuarantineDir: quarantine}
+	rep, err = Sweep(ctx, st, refs, opts)
+	if err != nil {
+		t.Fatalf("Sweep: %v", err)
+	}
+	if rep.Removed != 2 || len(rep.Errors) != 0 {
+		t.Fatalf("report: %+v", rep)
+	}
+	for _, key := range []string{"images/old.png", "images/w160/old.png"} {
+		if _, err := st.Stat(ctx, key); !errors.Is(err, blob.ErrNotFound) {
+			t.Errorf("%s not removed: %v", key, err)
+		}
+		if _, err := os.Stat(filepath.Join(quarantine, filepath.FromSlash(key))); err != nil {
+			t.Errorf("%s not quarantined: %v", key, err)
+		}
+	}
+	for _, key := range []string{"images/kept.png", "images/w160/kept.png", "images/new.png"} {
+		if _, err := st.Stat(ctx, key); err != nil {
+			t.Errorf("%s removed: %v", key, err)
+		}
+	}
+}
+
+func TestSweep_RechecksReferences(t *testing.T) {
+	ctx := context.Background()
+	st := blob.NewLocal(t.TempDir())
+	if err := st.Put(ctx, "images/late.png", []byte("x"), "image/png"); err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{StillReferenced: func(u string) bool { return u == "/files/images/late.png" }}
+	rep, err := Sweep(ctx, st, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Removed != 0 || rep.Referenced != 1 {
+		t.Fatalf("report: %+v", rep)
+	}
+}
+
+func TestSweep_ReuploadRestartsGrace(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	st := blob.NewLocal(dir)
+	if err := st.Put(ctx, "images/again.png", []byte("x"), "image/png"); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "images", "again.png"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	// the blob file is old, but its bytes were just uploaded again
+	opts := Options{Grace: 24 * time.Hour, LastUploaded: func(string) time.Time { return time.Now() }}
+	rep, err := Sweep(ctx, st, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Removed != 0 || rep.Young != 1 {
+		t.Fatalf("report: %+v", rep)
+	}
+}
+
+func TestOptions_ValidateQuarantineDir(t *testing.T) {
+	uploads := t.TempDir()
+	t.Setenv("UPLOAD_DIR", uploads)
+	for dir, ok := range map[string]bool{
+		"":                                   true,
+		t.TempDir():                          true,
+		uploads + "-quarantine":              true,
+		uploads:                              false,
+		filepath.Join(uploads, "quarantine"): false,
+	} {
+		if err := (Options{QuarantineDir: dir}).Validate(); (err == nil) != ok {
+			t.Errorf("Validate(%q) = %v, want ok=%v", dir, err, ok)
+		}
+	}
+	if _, err := Run(context.Background(), Options{QuarantineDir: filepath.Join(uploads, "q")}); err == nil {
+		t.Error("Run accepted a quarantine dir inside UPLOAD_DIR")
+	}
+}
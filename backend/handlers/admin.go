@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"learnlang-backend/models"
+	"learnlang-backend/store"
+	"learnlang-backend/utils"
+)
+
+// RequireAdmin guards admin routes with a bearer token compared against
+// ADMIN_TOKEN. Without ADMIN_TOKEN the admin API is disabled.
+func RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := os.Getenv("ADMIN_TOKEN")
+		if want == "" {
+			utils.WriteErrorWithRequest(w, r, http.StatusForbidden, utils.CodeForbidden, "admin API is disabled; set ADMIN_TOKEN")
+			return
+		}
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(want)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			utils.WriteErrorWithRequest(w, r, http.StatusUnauthorized, utils.CodeUnauthorized, "missing or invalid admin token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// LanguageRequestDTO is the request body for creating or updating a language.
+// On update, omitted fields keep their value.
+type LanguageRequestDTO struct {
+	Name       *string `json:"name"`        // English name; derived from code if blank
+	Code       *string `json:"code"`        // ISO 639-1 or 639-3; fixed once created
+	NativeName *string `json:"native_name"` // derived from code if blank
+	Script     *string `json:"script"`      // ISO 15924; derived from code if blank
+	Direction  *string `json:"direction"`   // "ltr" or "rtl"; derived from script if blank
+	Collation  *string `json:"collation"`   // BCP 47 locale; defaults to code
+	Enabled    *bool   `json:"enabled"`     // defaults to true
+}
+
+func (req LanguageRequestDTO) apply(l *models.Language) {
+	for _, f := range []struct {
+		src *string
+		dst *string
+	}{
+		{req.Name, &l.Name}, {req.Code, &l.Code}, {req.NativeName, &l.NativeName},
+		{req.Script, &l.Script}, {req.Direction, &l.Direction}, {req.Collation, &l.Collation},
+	} {
+		if f.src != nil {
+			*f.dst = *f.src
+		}
+	}
+	if req.Enabled != nil {
+		l.Enabled = *req.Enabled
+	}
+}
+
+// AdminListLanguagesHandler returns every language, including disabled ones.
+func AdminListLanguagesHandler(w http.ResponseWriter, r *http.Request) {
+	langs := store.AllLanguages()
+	utils.WriteOKData(w, langs, map[string]any{"count": len(langs)})
+}
+
+// AdminCreateLanguageHandler adds a language. Only code is required.
+func AdminCreateLanguageHandler(w http.ResponseWriter, r *http.Request) {
+	var req LanguageRequestDTO
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	if req.Code == nil || strings.TrimSpace(*req.Code) == "" {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeMissingFields, "missing required field(s): code")
+		return
+	}
+	l := models.Language{ID: uuid.New().String(), Enabled: true}
+	req.apply(&l)
+	if err := utils.NormalizeLanguage(&l); err != nil {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidLanguage, err.Error())
+		return
+	}
+	if _, exists := store.GetLanguageByCode(l.Code); exists {
+		utils.WriteErrorWithRequest(w, r, http.StatusConflict, utils.CodeDuplicateLanguage, fmt.Sprintf("language %q already exists", l.Code))
+		return
+	}
+	if err := store.CreateLanguage(l); err != nil {
+		utils.WriteErrorWithRequest(w, r, http.StatusInternalServerError, utils.CodeInternal, "failed to create language")
+		return
+	}
+	utils.WriteCreatedData(w, l, nil)
+}
+
+// AdminUpdateLanguageHandler changes the metadata of a language or
+// re-enables it. The code cannot change, since stored vocabs are normalised
+// with its rules.
+func AdminUpdateLanguageHandler(w http.ResponseWriter, r *http.Request) {
+	l, ok := adminLanguage(w, r)
+	if !ok {
+		return
+	}
+	var req LanguageRequestDTO
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	code := l.Code
+	req.apply(&l)
+	if err := utils.NormalizeLanguage(&l); err != nil {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidLanguage, err.Error())
+		return
+	}
+	if l.Code != code {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidLanguage, fmt.Sprintf("language code %q cannot be changed", code))
+		return
+	}
+	saveLanguage(w, r, l)
+}
+
+// AdminDisableLanguageHandler hides a language from GET /api/languages and
+// new packs. Existing packs keep working; PUT with enabled=true undoes it.
+func AdminDisableLanguageHandler(w http.ResponseWriter, r *http.Request) {
+	l, ok := adminLanguage(w, r)
+	if !ok {
+		return
+	}
+	l.Enabled = false
+	saveLanguage(w, r, l)
+}
+
+// adminLanguage loads the language named by the {id} URL param, writing a
+// 404 if it does not exist.
+func adminLanguage(w http.ResponseWriter, r *http.Request) (models.Language, bool) {
+	id := strings.TrimSpace(chi.URLParam(r, "id"))
+	l, ok := store.GetLanguageByID(id)
+	if !ok {
+		utils.WriteErrorWithRequest(w, r, http.StatusNotFound, utils.CodeInvalidLanguage, fmt.Sprintf("unknown language id: %q", id))
+	}
+	return l, ok
+}
+
+func saveLanguage(w http.ResponseWriter, r *http.Request, l models.Language) {
+	if err := store.UpdateLanguage(l); err != nil {
+		if errors.Is(err, store.ErrLanguageNotFound) {
+			utils.WriteErrorWithRequest(w, r, http.StatusNotFound, utils.CodeInvalidLanguage, fmt.Sprintf("unknown language id: %q", l.ID))
+			return
+		}
+		utils.WriteErrorWithRequest(w, r, http.StatusInternalServerError, utils.CodeInternal, "failed to update language")
+		return
+	}
+	utils.WriteOKData(w, l, nil)
+}
@@ -0,0 +1,97 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"learnlang-backend/models"
+)
+
+func TestAdminLanguages(t *testing.T) {
+	h := setup(t)
+	t.Setenv("ADMIN_TOKEN", "secret")
+
+	do := func(method, path, token string, body any) (int, models.Language) {
+		t.Helper()
+		var buf bytes.Buffer
+		if body != nil {
+			_ = json.NewEncoder(&buf).Encode(body)
+		}
+		req := httptest.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		var resp struct {
+			Data models.Language `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Data
+	}
+
+	if code, _ := do(http.MethodPost, "/api/admin/languages", "", map[string]string{"code": "ur"}); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", code)
+	}
+	if code, _ := do(http.MethodPost, "/api/admin/languages", "secret", map[string]string{"code": "xx"}); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown code, got %d", code)
+	}
+	if code, _ := do(http.MethodPost, "/api/admin/languages", "secret", map[string]string{"code": "deu"}); code != http.StatusConflict {
+		t.Fatalf("expected 409 for existing language, got %d", code)
+	}
+
+	code, ur := do(http.MethodPost, "/api/admin/languages", "secret", map[string]string{"code": "urd"})
+	if code != http.StatusCreated || ur.Code != "ur" || ur.Script != "Arab" || ur.Direction != "rtl" || !ur.Enabled {
+		t.Fatalf("unexpected create response %d %+v", code, ur)
+	}
+	if code, got := do(http.MethodPut, "/api/admin/languages/"+ur.ID, "secret", map[string]string{"native_name": "اُردُو"}); code != http.StatusOK || got.NativeName != "اُردُو" || got.Direction != "rtl" {
+		t.Fatalf("unexpected update response %d %+v", code, got)
+	}
+	if code, _ := do(http.MethodPut, "/api/admin/languages/"+ur.ID, "secret", map[string]string{"code": "ar"}); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 when changing code, got %d", code)
+	}
+
+	listed := func() bool {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/languages", nil))
+		var resp struct {
+			Data []models.Language `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		for _, l := range resp.Data {
+			if l.ID == ur.ID {
+				return true
+			}
+		}
+		return false
+	}
+	if !listed() {
+		t.Fatalf("new language missing from /api/languages")
+	}
+	if code, got := do(http.MethodDelete, "/api/admin/languages/"+ur.ID, "secret", nil); code != http.StatusOK || got.Enabled {
+		t.Fatalf("unexpected disable response %d %+v", code, got)
+	}
+	if listed() {
+		t.Fatalf("disabled language still listed")
+	}
+	body, _ := json.Marshal(createPackReq{Name: "Basics", LangID: ur.ID, UserID: "u1"})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/packs", bytes.NewReader(body)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for pack in disabled language, got %d", w.Code)
+	}
+}
+
+func TestAdminLanguages_DisabledWithoutToken(t *testing.T) {
+	h := setup(t)
+	t.Setenv("ADMIN_TOKEN", "")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/languages", nil))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 when ADMIN_TOKEN is unset, got %d", w.Code)
+	}
+}
@@ -0,0 +1,380 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"learnlang-backend/models"
+	"learnlang-backend/store"
+	"learnlang-backend/utils"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxBatchOps       = 200
+	maxBatchBodyBytes = 100 << 20 // 100MB across all parts
+)
+
+// Per-operation outcomes reported by the batch endpoint.
+const (
+	batchCreated    = "created"
+	batchUpdated    = "updated"
+	batchDeleted    = "deleted"
+	batchError      = "error"
+	batchNotApplied = "not_applied"
+)
+
+// BatchVocabOpDTO is one operation in a batch request.
+// Image names a multipart file part holding the image to upload. The detail
+// fields mean the same as in CreateVocabRequestDTO; an update only changes
+// the ones present (an empty list or string clears it).
+type BatchVocabOpDTO struct {
+	Op          string `json:"op"` // create, update or delete
+	ID          string `json:"id"` // update/delete
+	PackID      string `json:"pack_id"`
+	Name        string `json:"name"`
+	Translation string `json:"translation"`
+	Image       string `json:"image"`
+
+	Examples        []models.Example `json:"examples"`
+	Notes           *string          `json:"notes"`
+	PartOfSpeech    *string          `json:"part_of_speech"`
+	Tags            []string         `json:"tags"`
+	Grammar         *models.Grammar  `json:"grammar"`
+	Transliteration *string          `json:"transliteration"`
+	Translations    []string         `json:"translations"`
+	Synonyms        []string         `json:"synonyms"`
+	Difficulty      *int             `json:"difficulty"`
+}
+
+// applyDetails copies the detail fields present in op onto v.
+func (op BatchVocabOpDTO) applyDetails(v *models.Vocab) {
+	if op.Examples != nil {
+		v.Examples = op.Examples
+	}
+	if op.Notes != nil {
+		v.Notes = *op.Notes
+	}
+	if op.PartOfSpeech != nil {
+		v.PartOfSpeech = *op.PartOfSpeech
+	}
+	if op.Tags != nil {
+		v.Tags = op.Tags
+	}
+	if op.Grammar != nil {
+		v.Grammar = op.Grammar
+	}
+	if op.Transliteration != nil {
+		v.Transliteration = *op.Transliteration
+	}
+	if op.Translations != nil {
+		// keep the primary translation first; the rest are replaced
+		v.Translations = append([]string{v.Translation}, op.Translations...)
+	}
+	if op.Synonyms != nil {
+		v.Synonyms = op.Synonyms
+	}
+	if op.Difficulty != nil {
+		v.Difficulty = *op.Difficulty
+	}
+}
+
+// BatchVocabOpResult reports the outcome of one operation, by position.
+type BatchVocabOpResult struct {
+	Index  int           `json:"index"`
+	Op     string        `json:"op"`
+	ID     string        `json:"id,omitempty"`
+	Status string        `json:"status"`
+	Vocab  *models.Vocab `json:"vocab,omitempty"`
+	Error  string        `json:"error,omitempty"`
+	Code   string        `json:"code,omitempty"`
+}
+
+// BatchVocabsHandler applies create/update/delete operations in one transaction.
+// Accepts either a JSON array body, or multipart/form-data with the array in the
+// "operations" field and images as file parts referenced by part name.
+// Either every operation is applied or none is.
+func BatchVocabsHandler(w http.ResponseWriter, r *http.Request) {
+	var ops []BatchVocabOpDTO
+	var files map[string][]*multipart.FileHeader
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		r.Body = http.MaxBytesReader(w, r.Body, maxBatchBodyBytes)
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidJSON, "invalid multipart form")
+			return
+		}
+		files = r.MultipartForm.File
+		raw := r.FormValue("operations")
+		if strings.TrimSpace(raw) == "" {
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeMissingFields, "missing required field(s): operations")
+			return
+		}
+		dec := json.NewDecoder(bytes.NewReader([]byte(raw)))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&ops); err != nil {
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidJSON, fmt.Sprintf("invalid operations: %v", err))
+			return
+		}
+	} else if !decodeJSONBody(w, r, &ops) {
+		return
+	}
+	if len(ops) == 0 {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeMissingFields, "operations must not be empty")
+		return
+	}
+	if len(ops) > maxBatchOps {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, fmt.Sprintf("too many operations; max %d", maxBatchOps))
+		return
+	}
+
+	results := make([]BatchVocabOpResult, len(ops))
+	planned := make([]store.VocabOp, len(ops))
+	b := newBatchPlanner()
+	var failed int
+	for i, op := range ops {
+		results[i] = BatchVocabOpResult{Index: i, Op: op.Op, ID: strings.TrimSpace(op.ID)}
+		if op.Image != "" && len(files[op.Image]) == 0 {
+			results[i].Status, results[i].Code, results[i].Error = batchError, utils.CodeMissingFields, fmt.Sprintf("image part %q not found", op.Image)
+			failed++
+			continue
+		}
+		vop, status, code, msg := b.plan(op)
+		if code != "" {
+			results[i].Status, results[i].Code, results[i].Error = batchError, code, msg
+			failed++
+			continue
+		}
+		planned[i] = vop
+		results[i].ID = vop.Vocab.ID
+		results[i].Status = status
+	}
+	if failed > 0 {
+		markNotApplied(results)
+		utils.WriteData(w, http.StatusBadRequest, results, map[string]any{"applied": false, "failed": failed})
+		return
+	}
+
+	// Save images only once the whole batch validated. Images saved before a
+	// failure are content-addressed and may already be shared with another
+	// request, so they are left for the grace-period garbage collector.
+	for i, op := range ops {
+		if op.Image == "" || planned[i].Kind == store.OpDelete {
+			continue
+		}
+		fh := files[op.Image][0]
+		f, err := fh.Open()
+		if err != nil {
+			results[i].Status, results[i].Code, results[i].Error = batchError, utils.CodeInvalidFileType, "invalid uploaded file"
+			markNotApplied(results)
+			utils.WriteData(w, http.StatusBadRequest, results, map[string]any{"applied": false, "failed": 1})
+			return
+		}
+		img, uerr := saveImageUpload(w, f, fh)
+		f.Close()
+		if uerr != nil {
+			results[i].Status, results[i].Code, results[i].Error = batchError, uerr.Code, uerr.Msg
+			markNotApplied(results)
+			utils.WriteData(w, uerr.Status, results, map[string]any{"applied": false, "failed": 1})
+			return
+		}
+		planned[i].Vocab.Image = img.URL
+	}
+
+	if idx, err := store.ApplyVocabOps(planned); err != nil {
+		status, code, msg := http.StatusInternalServerError, utils.CodeInternal, "failed to apply operation"
+		if store.IsUniqueViolation(err) {
+			// a concurrent request claimed the name after planning
+			status, code, msg = http.StatusConflict, utils.CodeDuplicateVocab, "vocab already exists in this pack"
+		}
+		if idx >= 0 {
+			results[idx].Status, results[idx].Code, results[idx].Error = batchError, code, msg
+		}
+		markNotApplied(results)
+		utils.WriteData(w, status, results, map[string]any{"applied": false, "failed": 1})
+		return
+	}
+	for i := range results {
+		if planned[i].Kind != store.OpDelete {
+			v := planned[i].Vocab
+			v.ImageVariants = utils.ImageVariants(v.Image)
+			results[i].Vocab = &v
+		}
+	}
+	utils.WriteOKData(w, results, map[string]any{"applied": true, "count": len(results)})
+}
+
+// markNotApplied flags every operation that did not itself fail.
+func markNotApplied(results []BatchVocabOpResult) {
+	for i := range results {
+		if results[i].Status != batchError {
+			results[i].Status = batchNotApplied
+		}
+	}
+}
+
+// batchPlanner validates operations against the store plus the effects of
+// earlier operations in the same batch (renames, deletes, new names).
+type batchPlanner struct {
+	current map[string]models.Vocab // vocab state after earlier ops, by ID
+	deleted map[string]bool         // vocab IDs deleted earlier in the batch
+	added   map[string]bool         // vocab keys claimed earlier in the batch
+	freed   map[string]bool         // stored vocab keys released earlier in the batch
+	langs   map[string]string       // language code by pack ID
+}
+
+func newBatchPlanner() *batchPlanner {
+	return &batchPlanner{
+		current: map[string]models.Vocab{},
+		deleted: map[string]bool{},
+		added:   map[string]bool{},
+		freed:   map[string]bool{},
+		langs:   map[string]string{},
+	}
+}
+
+// key returns the vocab key of name in a pack, folded for the pack's language.
+func (b *batchPlanner) key(packID, name string) string {
+	lang, ok := b.langs[packID]
+	if !ok {
+		pack, _ := store.GetPackByID(packID)
+		lang = languageCode(pack.LangID)
+		b.langs[packID] = lang
+	}
+	return utils.MakeVocabKeyByPackID(packID, lang, name)
+}
+
+// taken reports whether key is in use once earlier ops are applied.
+func (b *batchPlanner) taken(key string) bool {
+	return b.added[key] || (!b.freed[key] && store.VocabExistsByKey(key))
+}
+
+func (b *batchPlanner) claim(key string) {
+	b.added[key] = true
+}
+
+func (b *batchPlanner) release(key string) {
+	delete(b.added, key)
+	b.freed[key] = true
+}
+
+// vocab returns the vocab as seen after earlier ops in the batch.
+func (b *batchPlanner) vocab(id string) (models.Vocab, bool) {
+	if b.deleted[id] {
+		return models.Vocab{}, false
+	}
+	if v, ok := b.current[id]; ok {
+		return v, true
+	}
+	return store.GetVocabByID(id)
+}
+
+// plan validates one operation and returns the store op and success status,
+// or an error code and message.
+func (b *batchPlanner) plan(op BatchVocabOpDTO) (store.VocabOp, string, string, string) {
+	name := strings.TrimSpace(op.Name)
+	translation := strings.TrimSpace(op.Translation)
+	switch op.Op {
+	case store.OpCreate:
+		packID := strings.TrimSpace(op.PackID)
+		missing := make([]string, 0, 4)
+		if packID == "" {
+			missing = append(missing, "pack_id")
+		}
+		if op.Image == "" {
+			missing = append(missing, "image")
+		}
+		if name == "" {
+			missing = append(missing, "name")
+		}
+		if translation == "" && len(op.Translations) > 0 {
+			translation = strings.TrimSpace(op.Translations[0])
+			op.Translations = op.Translations[1:]
+		}
+		if translation == "" {
+			missing = append(missing, "translation")
+		}
+		if len(missing) > 0 {
+			return store.VocabOp{}, "", utils.CodeMissingFields, fmt.Sprintf("missing required field(s): %s", strings.Join(missing, ", "))
+		}
+		v := models.Vocab{ID: uuid.New().String(), Name: name, Translation: translation, PackID: packID}
+		op.applyDetails(&v)
+		if err := utils.NormalizeVocabDetails(&v); err != nil {
+			return store.VocabOp{}, "", utils.CodeInvalidParam, err.Error()
+		}
+		pack, ok := store.GetPackByID(packID)
+		if !ok {
+			return store.VocabOp{}, "", utils.CodeInvalidPack, fmt.Sprintf("unknown pack id: %q", packID)
+		}
+		langCode := languageCode(pack.LangID)
+		b.langs[packID] = langCode
+		if err := utils.NormalizeGrammar(langCode, &v); err != nil {
+			return store.VocabOp{}, "", utils.CodeInvalidParam, err.Error()
+		}
+		if v.Transliteration == "" {
+			v.Transliteration = utils.AutoTransliteration(langCode, v)
+		}
+		key := b.key(packID, name)
+		if b.taken(key) {
+			return store.VocabOp{}, "", utils.CodeDuplicateVocab, fmt.Sprintf("vocab %q already exists in this pack", name)
+		}
+		b.claim(key)
+		b.current[v.ID] = v
+		return store.VocabOp{Kind: store.OpCreate, Vocab: v}, batchCreated, "", ""
+	case store.OpUpdate, store.OpDelete:
+		id := strings.TrimSpace(op.ID)
+		if id == "" {
+			return store.VocabOp{}, "", utils.CodeMissingFields, "missing required field(s): id"
+		}
+		v, ok := b.vocab(id)
+		if !ok {
+			return store.VocabOp{}, "", utils.CodeInvalidVocab, fmt.Sprintf("unknown vocab id: %q", id)
+		}
+		oldKey := b.key(v.PackID, v.Name)
+		if op.Op == store.OpDelete {
+			b.release(oldKey)
+			b.deleted[id] = true
+			return store.VocabOp{Kind: store.OpDelete, Vocab: v}, batchDeleted, "", ""
+		}
+		source := utils.TransliterationSource(v)
+		newKey := oldKey
+		if name != "" {
+			newKey = b.key(v.PackID, name)
+			v.Name = name
+		}
+		if translation != "" {
+			// A new primary replaces the old one among the accepted translations.
+			if len(v.Translations) > 0 {
+				v.Translations[0] = translation
+			}
+			v.Translation = translation
+		}
+		op.applyDetails(&v)
+		if err := utils.NormalizeVocabDetails(&v); err != nil {
+			return store.VocabOp{}, "", utils.CodeInvalidParam, err.Error()
+		}
+		// Re-check grammar even if unchanged: it depends on name and part of speech.
+		langCode := b.langs[v.PackID]
+		if err := utils.NormalizeGrammar(langCode, &v); err != nil {
+			return store.VocabOp{}, "", utils.CodeInvalidParam, err.Error()
+		}
+		if v.Transliteration == "" || op.Transliteration == nil && utils.TransliterationSource(v) != source {
+			v.Transliteration = utils.AutoTransliteration(langCode, v)
+		}
+		if newKey != oldKey {
+			if b.taken(newKey) {
+				return store.VocabOp{}, "", utils.CodeDuplicateVocab, fmt.Sprintf("vocab %q already exists in this pack", name)
+			}
+			b.release(oldKey)
+			b.claim(newKey)
+		}
+		b.current[id] = v
+		return store.VocabOp{Kind: store.OpUpdate, Vocab: v}, batchUpdated, "", ""
+	default:
+		return store.VocabOp{}, "", utils.CodeInvalidParam, fmt.Sprintf("unsupported op: %q", op.Op)
+	}
+}
@@ -0,0 +1,132 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"learnlang-backend/models"
+	"learnlang-backend/store"
+)
+
+func newBatchReq(t *testing.T, ops []map[string]any, images ...string) *http.Request {
+	t.Helper()
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	raw, _ := json.Marshal(ops)
+	if err := mw.WriteField("operations", string(raw)); err != nil {
+		t.Fatal(err)
+	}
+	for _, part := range images {
+		fw, err := mw.CreateFormFile(part, part+".png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write(tinyPNG()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/vocabs/batch", body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestBatchVocabs_Applies(t *testing.T) {
+	h := setup(t)
+	store.CreatePack(models.Pack{ID: "p1", Name: "Animals", LangID: "1", UserID: "u1"}, "")
+	store.CreateVocab(models.Vocab{ID: "old", Image: "/files/images/old.png", Name: "cow", Translation: "गाय", PackID: "p1"}, "")
+	store.CreateVocab(models.Vocab{ID: "gone", Image: "/files/images/gone.png", Name: "cat", Translation: "बिल्ली", PackID: "p1"}, "")
+
+	ops := []map[string]any{
+		{"op": "delete", "id": "gone"},
+		{"op": "create", "pack_id": "p1", "name": "cat", "translation": "बिल्ली", "image": "img1"},
+		{"op": "update", "id": "old", "translation": "गौ"},
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newBatchReq(t, ops, "img1"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if _, ok := store.GetVocabByID("gone"); ok {
+		t.Fatalf("expected deleted vocab to be gone")
+	}
+	if v, _ := store.GetVocabByID("old"); v.Translation != "गौ" {
+		t.Fatalf("expected updated translation, got %q", v.Translation)
+	}
+	if n := len(store.ListVocabsByPackID("p1", store.VocabSort{})); n != 2 {
+		t.Fatalf("expected 2 vocabs, got %d", n)
+	}
+}
+
+func TestBatchVocabs_RollsBackOnConflict(t *testing.T) {
+	h := setup(t)
+	store.CreatePack(models.Pack{ID: "p1", Name: "Animals", LangID: "1", UserID: "u1"}, "")
+
+	ops := []map[string]any{
+		{"op": "create", "pack_id": "p1", "name": "dog", "translation": "कुत्ता", "image": "img1"},
+		{"op": "create", "pack_id": "p1", "name": "Dog", "translation": "कुत्ता", "image": "img1"},
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newBatchReq(t, ops, "img1"))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data []map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if resp.Data[0]["status"] != "not_applied" || resp.Data[1]["code"] != "DUPLICATE_VOCAB" {
+		t.Fatalf("unexpected results: %#v", resp.Data)
+	}
+	if n := len(store.ListVocabsByPackID("p1", store.VocabSort{})); n != 0 {
+		t.Fatalf("expected nothing applied, got %d vocabs", n)
+	}
+}
+
+func TestBatchVocabs_Details(t *testing.T) {
+	h := setup(t)
+	store.CreatePack(models.Pack{ID: "p1", Name: "Küche", LangID: "2", UserID: "u1"}, "")
+	store.CreateVocab(models.Vocab{ID: "v1", Image: "/files/images/a.png", Name: "Auto", Translation: "car", PackID: "p1",
+		Translations: []string{"car", "automobile"}}, "")
+
+	ops := []map[string]any{
+		{"op": "create", "pack_id": "p1", "name": "Messer", "translation": "knife", "image": "img1",
+			"translations": []string{"blade"}, "part_of_speech": "noun", "grammar": map[string]any{"gender": "neuter"}, "difficulty": 2},
+		{"op": "update", "id": "v1", "translation": "auto"},
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newBatchReq(t, ops, "img1"))
+	var resp struct {
+		Data []struct {
+			Vocab models.Vocab `json:"vocab"`
+		} `json:"data"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if w.Code != http.StatusOK || len(resp.Data) != 2 {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	created, _ := store.GetVocabByID(resp.Data[0].Vocab.ID)
+	if created.Difficulty != 2 || created.Grammar == nil || created.Grammar.Gender != "neuter" || len(created.Translations) != 2 {
+		t.Fatalf("expected details to be stored, got %+v", created)
+	}
+	// the old primary must not linger among the accepted translations
+	updated, _ := store.GetVocabByID("v1")
+	if len(updated.Translations) != 2 || updated.Translations[0] != "auto" || updated.Translations[1] != "automobile" {
+		t.Fatalf("expected translations [auto automobile], got %q", updated.Translations)
+	}
+
+	bad := []map[string]any{{"op": "update", "id": "v1", "difficulty": 9}}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newBatchReq(t, bad))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected invalid difficulty to be rejected, got %d %s", w.Code, w.Body.String())
+	}
+}
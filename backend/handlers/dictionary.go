@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"learnlang-backend/store"
+	"learnlang-backend/utils"
+)
+
+// GetDictionaryHandler looks up words in the imported offline dictionary by
+// headword prefix, to prefill a vocab's translation, part of speech and gender.
+// Required query: lang_id, q. Optional: source_lang_id (language of the
+// definitions), limit (default 20, max 100). Exact matches come first.
+func GetDictionaryHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	langID := strings.TrimSpace(q.Get("lang_id"))
+	prefix := strings.TrimSpace(q.Get("q"))
+	missing := make([]string, 0, 2)
+	for _, p := range []struct{ name, value string }{{"lang_id", langID}, {"q", prefix}} {
+		if p.value == "" {
+			missing = append(missing, p.name)
+		}
+	}
+	if len(missing) > 0 {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeMissingFields, fmt.Sprintf("missing required query param(s): %s", strings.Join(missing, ", ")))
+		return
+	}
+	if utf8.RuneCountInString(prefix) > utils.MaxTranslationLen {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, fmt.Sprintf("q too long; max %d characters", utils.MaxTranslationLen))
+		return
+	}
+	if !languageIDExists(langID) {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidLanguage, fmt.Sprintf("unsupported lang_id: %q", langID))
+		return
+	}
+	sourceLangID := strings.TrimSpace(q.Get("source_lang_id"))
+	if sourceLangID != "" && !languageIDExists(sourceLangID) {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidLanguage, fmt.Sprintf("unsupported source_lang_id: %q", sourceLangID))
+		return
+	}
+	limit, _ := parsePaging(q, 20, 100)
+	entries := store.SearchDictionary(store.DictionaryFilter{LangID: langID, SourceLangID: sourceLangID, Prefix: prefix, Limit: limit})
+	utils.WriteOKData(w, entries, map[string]any{"count": len(entries)})
+}
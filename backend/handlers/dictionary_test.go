@@ -0,0 +1,65 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"learnlang-backend/dictionary"
+	"learnlang-backend/models"
+)
+
+func TestGetDictionary_PrefixLookup(t *testing.T) {
+	h := setup(t)
+	dump := "Messer\tn\tdas\tknife\n" +
+		"Messerstich\tn\tm\tstab\n" +
+		"messen\tv\t\tto measure\n" +
+		"Mädchen\tn\tn\tgirl\n" +
+		"Messer\tn\t\tblade\tknife\n" // merged into the first entry
+	rep, err := dictionary.Import(context.Background(), strings.NewReader(dump), dictionary.FormatTSV,
+		dictionary.Options{LangID: "2", SourceLangID: "3", Source: "test"})
+	if err != nil || rep.Read != 5 || rep.Imported != 5 {
+		t.Fatalf("import: %+v %v", rep, err)
+	}
+
+	get := func(query string) (int, []models.DictionaryEntry) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/dictionary?"+query, nil))
+		var resp struct {
+			Data []models.DictionaryEntry `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Data
+	}
+	code, got := get("lang_id=2&q=MESSER")
+	if code != http.StatusOK || len(got) != 2 {
+		t.Fatalf("expected 2 entries, got %d %+v", code, got)
+	}
+	if e := got[0]; e.Headword != "Messer" || e.PartOfSpeech != "noun" || e.Gender != "neuter" ||
+		strings.Join(e.Definitions, ",") != "knife,blade" {
+		t.Fatalf("unexpected exact match %+v", e)
+	}
+	if got[1].Headword != "Messerstich" || got[1].Gender != "masculine" {
+		t.Fatalf("unexpected second entry %+v", got[1])
+	}
+	if _, got := get("lang_id=2&q=mes&limit=1"); len(got) != 1 {
+		t.Fatalf("limit not applied: %+v", got)
+	}
+	// headwords are case-folded like vocab names
+	if _, got := get("lang_id=2&q=MÄDCH&source_lang_id=3"); len(got) != 1 || got[0].Headword != "Mädchen" {
+		t.Fatalf("folded lookup failed: %+v", got)
+	}
+	if _, got := get("lang_id=1&q=mes"); len(got) != 0 {
+		t.Fatalf("expected no Hindi entries, got %+v", got)
+	}
+	if code, _ := get("lang_id=2"); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without q, got %d", code)
+	}
+	if code, _ := get("lang_id=99&q=a"); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown language, got %d", code)
+	}
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"learnlang-backend/models"
+	"learnlang-backend/store"
+	"learnlang-backend/utils"
+)
+
+// Merge modes accepted by MergeVocabsHandler.
+const (
+	mergeModeMerge = "merge"
+	mergeModeLink  = "link"
+)
+
+// MergeVocabsRequestDTO is the request body for consolidating duplicate vocabs.
+type MergeVocabsRequestDTO struct {
+	UserID      string   `json:"user_id"`
+	KeepID      string   `json:"keep_id"`     // vocab that survives (merge) or becomes canonical (link)
+	VocabIDs    []string `json:"vocab_ids"`   // duplicates to merge into / link to keep_id
+	Mode        string   `json:"mode"`        // "merge" (default) or "link"
+	Translation string   `json:"translation"` // optional new translation for the kept vocab (merge only)
+	Image       string   `json:"image"`       // optional image of one of the duplicates to keep (merge only)
+}
+
+// GetDuplicateVocabsHandler reports vocabs duplicated across a user's packs in one language.
+// Required query: user_id, lang_id.
+func GetDuplicateVocabsHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	userID := strings.TrimSpace(q.Get("user_id"))
+	lang := strings.TrimSpace(q.Get("lang_id"))
+	if userID == "" || lang == "" {
+		miss := make([]string, 0, 2)
+		if userID == "" {
+			miss = append(miss, "user_id")
+		}
+		if lang == "" {
+			miss = append(miss, "lang_id")
+		}
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeMissingFields, fmt.Sprintf("missing required query param(s): %s", strings.Join(miss, ", ")))
+		return
+	}
+	if !languageIDExists(lang) {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidLanguage, fmt.Sprintf("unsupported language id: %q", lang))
+		return
+	}
+	groups := store.ListDuplicateVocabs(userID, lang)
+	utils.WriteOKDataCached(w, r, groups, map[string]any{"count": len(groups)})
+}
+
+// MergeVocabsHandler consolidates duplicate vocabs into one, or links them to a canonical vocab.
+// All vocabs must belong to the caller's packs in one language and share a duplicate key.
+func MergeVocabsHandler(w http.ResponseWriter, r *http.Request) {
+	var req MergeVocabsRequestDTO
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	req.UserID = strings.TrimSpace(req.UserID)
+	req.KeepID = strings.TrimSpace(req.KeepID)
+	missing := make([]string, 0, 3)
+	if req.UserID == "" {
+		missing = append(missing, "user_id")
+	}
+	if req.KeepID == "" {
+		missing = append(missing, "keep_id")
+	}
+	if len(req.VocabIDs) == 0 {
+		missing = append(missing, "vocab_ids")
+	}
+	if len(missing) > 0 {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeMissingFields, fmt.Sprintf("missing required field(s): %s", strings.Join(missing, ", ")))
+		return
+	}
+	switch req.Mode {
+	case "":
+		req.Mode = mergeModeMerge
+	case mergeModeMerge, mergeModeLink:
+	default:
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, fmt.Sprintf("unsupported mode: %q", req.Mode))
+		return
+	}
+
+	keep, ok := store.GetVocabByID(req.KeepID)
+	if !ok {
+		utils.WriteErrorWithRequest(w, r, http.StatusNotFound, utils.CodeInvalidVocab, fmt.Sprintf("unknown vocab id: %q", req.KeepID))
+		return
+	}
+	pack, ok := store.GetPackByID(keep.PackID)
+	if !ok || pack.UserID != req.UserID {
+		utils.WriteErrorWithRequest(w, r, http.StatusForbidden, utils.CodeInvalidVocab, fmt.Sprintf("vocab %q does not belong to user %q", req.KeepID, req.UserID))
+		return
+	}
+	// The duplicate report already scopes by owner and language; every id must be in keep's group.
+	var group models.DuplicateGroup
+	for _, g := range store.ListDuplicateVocabs(req.UserID, pack.LangID) {
+		for _, d := range g.Vocabs {
+			if d.ID == keep.ID {
+				group = g
+			}
+		}
+	}
+	members := make(map[string]models.DuplicateVocab, len(group.Vocabs))
+	for _, d := range group.Vocabs {
+		members[d.ID] = d
+	}
+	ids := make([]string, 0, len(req.VocabIDs))
+	seen := map[string]bool{keep.ID: true}
+	for _, id := range req.VocabIDs {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		if _, ok := members[id]; !ok {
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidVocab, fmt.Sprintf("vocab %q is not a duplicate of %q", id, keep.ID))
+			return
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidVocab, "vocab_ids must list at least one other duplicate")
+		return
+	}
+
+	if req.Mode == mergeModeLink {
+		if err := store.LinkVocabs(keep.ID, ids); err != nil {
+			utils.WriteErrorWithRequest(w, r, http.StatusInternalServerError, utils.CodeInternal, "failed to link vocabs")
+			return
+		}
+		utils.WriteOKData(w, keep, map[string]any{"mode": req.Mode, "linked": ids})
+		return
+	}
+
+	merged := make([]models.Vocab, 0, len(ids))
+	for _, id := range ids {
+		if v, ok := store.GetVocabByID(id); ok {
+			merged = append(merged, v)
+		}
+	}
+	foldMergedVocabs(&keep, merged, strings.TrimSpace(req.Translation))
+	if err := utils.NormalizeVocabDetails(&keep); err != nil {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, err.Error())
+		return
+	}
+	if img := strings.TrimSpace(req.Image); img != "" && img != keep.Image {
+		var found bool
+		for _, id := range ids {
+			if members[id].Image == img {
+				found = true
+				break
+			}
+		}
+		if !found {
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, "image must belong to one of the merged vocabs")
+			return
+		}
+		keep.Image = img
+	}
+	if err := store.MergeVocabs(keep, ids); err != nil {
+		utils.WriteErrorWithRequest(w, r, http.StatusInternalServerError, utils.CodeInternal, "failed to merge vocabs")
+		return
+	}
+	utils.WriteOKData(w, keep, map[string]any{"mode": req.Mode, "merged": ids})
+}
+
+// foldMergedVocabs keeps what the merged vocabs knew: their translations
+// become alternates of keep and their names and synonyms become synonyms.
+// A non-empty translation becomes the primary; keep's previous primary stays
+// accepted as an alternate. Lists are capped at the per-vocab limits, keep's
+// entries first.
+func foldMergedVocabs(keep *models.Vocab, merged []models.Vocab, translation string) {
+	translations := append([]string{translation, keep.Translation}, keep.Translations...)
+	synonyms := append([]string{}, keep.Synonyms...)
+	for _, v := range merged {
+		translations = append(translations, v.Translation)
+		translations = append(translations, v.Translations...)
+		synonyms = append(synonyms, v.Name)
+		synonyms = append(synonyms, v.Synonyms...)
+	}
+	keep.Translations = capUnique(translations, utils.MaxVocabTranslations)
+	if len(keep.Translations) > 0 {
+		keep.Translation = keep.Translations[0]
+	}
+	keep.Synonyms = capUnique(synonyms, utils.MaxVocabSynonyms)
+}
+
+// capUnique drops empty and case-insensitively repeated items and keeps at
+// most max of the rest.
+func capUnique(items []string, max int) []string {
+	seen := map[string]bool{}
+	out := []string{}
+	for _, it := range items {
+		it = strings.TrimSpace(it)
+		if it == "" || seen[strings.ToLower(it)] || len(out) == max {
+			continue
+		}
+		seen[strings.ToLower(it)] = true
+		out = append(out, it)
+	}
+	return out
+}
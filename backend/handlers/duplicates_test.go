@@ -0,0 +1,91 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"learnlang-backend/models"
+	"learnlang-backend/store"
+)
+
+func seedDuplicates(t *testing.T) {
+	t.Helper()
+	store.CreatePack(models.Pack{ID: "p-a", Name: "Family", LangID: "2", UserID: "u1"}, "")
+	store.CreatePack(models.Pack{ID: "p-b", Name: "Work", LangID: "2", UserID: "u1"}, "")
+	store.CreateVocab(models.Vocab{ID: "v-a", Image: "/files/images/a.png", Name: "Müller", Translation: "miller", PackID: "p-a"}, "")
+	store.CreateVocab(models.Vocab{ID: "v-b", Image: "/files/images/b.png", Name: "muller", Translation: "the miller", PackID: "p-b"}, "")
+	store.CreateVocab(models.Vocab{ID: "v-c", Image: "/files/images/c.png", Name: "Haus", Translation: "house", PackID: "p-b"}, "")
+}
+
+func TestGetDuplicateVocabs(t *testing.T) {
+	h := setup(t)
+	seedDuplicates(t)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/vocabs/duplicates?user_id=u1&lang_id=2", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data []models.DuplicateGroup `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(resp.Data) != 1 || len(resp.Data[0].Vocabs) != 2 {
+		t.Fatalf("expected one group of two, got %#v", resp.Data)
+	}
+}
+
+func TestMergeVocabs_Merge(t *testing.T) {
+	h := setup(t)
+	seedDuplicates(t)
+
+	body, _ := json.Marshal(map[string]any{"user_id": "u1", "keep_id": "v-a", "vocab_ids": []string{"v-b"}, "image": "/files/images/b.png"})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/vocabs/merge", bytes.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if _, ok := store.GetVocabByID("v-b"); ok {
+		t.Fatalf("expected merged vocab to be deleted")
+	}
+	if v, _ := store.GetVocabByID("v-a"); v.Image != "/files/images/b.png" {
+		t.Fatalf("expected kept vocab to take merged image, got %q", v.Image)
+	}
+}
+
+func TestMergeVocabs_FoldsTranslationsAndNames(t *testing.T) {
+	h := setup(t)
+	seedDuplicates(t)
+
+	// the new primary was one of the merged vocab's translations
+	body, _ := json.Marshal(map[string]any{"user_id": "u1", "keep_id": "v-a", "vocab_ids": []string{"v-b"}, "translation": "the miller"})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/vocabs/merge", bytes.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	v, _ := store.GetVocabByID("v-a")
+	if v.Translation != "the miller" || len(v.Translations) != 2 || v.Translations[1] != "miller" {
+		t.Fatalf("expected [the miller miller], got %q %q", v.Translation, v.Translations)
+	}
+	if len(v.Synonyms) != 1 || v.Synonyms[0] != "muller" {
+		t.Fatalf("expected merged name as synonym, got %q", v.Synonyms)
+	}
+}
+
+func TestMergeVocabs_RejectsNonDuplicate(t *testing.T) {
+	h := setup(t)
+	seedDuplicates(t)
+
+	body, _ := json.Marshal(map[string]any{"user_id": "u1", "keep_id": "v-a", "vocab_ids": []string{"v-c"}, "mode": "link"})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/vocabs/merge", bytes.NewReader(body)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d body=%s", w.Code, w.Body.String())
+	}
+}
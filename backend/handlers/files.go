@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"learnlang-backend/blob"
+	"learnlang-backend/models"
+	"learnlang-backend/store"
+	"learnlang-backend/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// FilesHandler serves /files/* from the configured blob store. Backends that
+// can presign URLs redirect there when FILES_MODE=redirect; otherwise the
+// object is proxied through this server.
+func FilesHandler(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "*")
+	if !blob.ValidKey(key) {
+		http.NotFound(w, r)
+		return
+	}
+	serveBlob(w, r, key)
+}
+
+// ImageVariantHandler serves /files/images/w{width}/{name}, generating and
+// caching the variant on first request (covers images uploaded before variants
+// existed). Images that cannot be resized redirect to the full image.
+func ImageVariantHandler(w http.ResponseWriter, r *http.Request) {
+	variant := chi.URLParam(r, "variant")
+	name := chi.URLParam(r, "name")
+	ws, ok := strings.CutPrefix(variant, "w")
+	width, err := strconv.Atoi(ws)
+	if !ok || err != nil || !utils.IsImageVariantWidth(width) {
+		http.NotFound(w, r)
+		return
+	}
+	key, err := utils.EnsureImageVariant(r.Context(), name, width)
+	switch {
+	case errors.Is(err, utils.ErrVariantUnsupported):
+		http.Redirect(w, r, "/files/images/"+url.PathEscape(name), http.StatusFound)
+		return
+	case errors.Is(err, blob.ErrNotFound), errors.Is(err, utils.ErrInvalidImage):
+		http.NotFound(w, r)
+		return
+	case err != nil:
+		http.Error(w, "failed to generate image variant", http.StatusInternalServerError)
+		return
+	}
+	serveBlob(w, r, key)
+}
+
+// serveBlob writes the object at key, or redirects to a presigned URL.
+// Content-addressed images are cached as immutable; everything else must
+// revalidate. Only inline-safe media types are served as-is, so an uploaded
+// SVG or HTML file can never execute in the site's origin.
+func serveBlob(w http.ResponseWriter, r *http.Request, key string) {
+	h := w.Header()
+	h.Set("X-Content-Type-Options", "nosniff")
+	h.Set("Content-Security-Policy", "default-src 'none'; sandbox")
+
+	store := blob.Default()
+	if ps, ok := store.(blob.Presigner); ok && strings.EqualFold(os.Getenv("FILES_MODE"), "redirect") {
+		ttl := presignTTL()
+		u, err := ps.PresignGet(key, ttl)
+		if err != nil {
+			http.Error(w, "failed to sign file url", http.StatusInternalServerError)
+			return
+		}
+		// The redirect must not outlive the signature.
+		h.Set("Cache-Control", "private, max-age="+strconv.Itoa(int(ttl.Seconds()/2)))
+		http.Redirect(w, r, u, http.StatusFound)
+		return
+	}
+
+	rc, info, err := store.Get(r.Context(), key)
+	if errors.Is(err, blob.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, "failed to read file", http.StatusBadGateway)
+		return
+	}
+	defer rc.Close()
+
+	ct := info.ContentType
+	if !inlineSafe(ct) {
+		ct = "application/octet-stream"
+		h.Set("Content-Disposition", "attachment")
+	}
+	h.Set("Content-Type", ct)
+	etag := info.ETag
+	if hash, ok := contentHash(key); ok {
+		etag = `"` + hash + `"`
+		h.Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		h.Set("Cache-Control", "public, no-cache")
+	}
+	if etag != "" {
+		h.Set("ETag", etag)
+	}
+	if rs, ok := rc.(io.ReadSeeker); ok {
+		// ServeContent handles If-None-Match, If-Modified-Since and ranges.
+		http.ServeContent(w, r, path.Base(key), info.ModTime, rs)
+		return
+	}
+	if utils.ETagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if !info.ModTime.IsZero() {
+		h.Set("Last-Modified", info.ModTime.UTC().Format(http.TimeFormat))
+	}
+	if info.Size > 0 {
+		h.Set("Content-Length", strconv.FormatInt(info.Size, 10))
+	}
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = io.Copy(w, rc)
+}
+
+// inlineSafe lists the media types browsers may render from /files/.
+func inlineSafe(contentType string) bool {
+	ct, _, _ := strings.Cut(contentType, ";")
+	switch strings.TrimSpace(strings.ToLower(ct)) {
+	case "image/jpeg", "image/png", "image/gif", "image/webp",
+		"audio/mpeg", "audio/wav", "audio/ogg", "audio/flac", "audio/webm", "audio/mp4":
+		return true
+	}
+	return false
+}
+
+// contentHash returns the SHA-256 a content-addressed key is named after
+// (images/<hash>.<ext>, a variant of it, or audio/<hash>.<ext>). Such URLs
+// never change content.
+func contentHash(key string) (string, bool) {
+	name := path.Base(key)
+	hash, _, _ := strings.Cut(name, ".")
+	if len(hash) != 64 || strings.Trim(hash, "0123456789abcdef") != "" {
+		return "", false
+	}
+	if dir := path.Dir(key); dir != "images" && dir != "audio" {
+		// variants: images/w<width>/<hash>.<ext>
+		return hash + "-" + path.Base(dir), strings.HasPrefix(dir, "images/w")
+	}
+	return hash, true
+}
+
+// presignTTL reads FILES_PRESIGN_TTL (a Go duration), defaulting to 15 minutes.
+func presignTTL() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("FILES_PRESIGN_TTL")); err == nil && d > 0 {
+		return d
+	}
+	return 15 * time.Minute
+}
+
+// withImageVariants fills ImageVariants for vocabs returned to clients.
+func withImageVariants(vs []models.Vocab) []models.Vocab {
+	for i := range vs {
+		vs[i].ImageVariants = utils.ImageVariants(vs[i].Image)
+	}
+	return vs
+}
+
+// withAudio fills Audio for vocabs returned to clients.
+func withAudio(vs []models.Vocab) []models.Vocab {
+	ids := make([]string, len(vs))
+	for i, v := range vs {
+		ids[i] = v.ID
+	}
+	clips := store.VocabAudio(ids)
+	for i := range vs {
+		vs[i].Audio = clips[vs[i].ID]
+	}
+	return vs
+}
+
+// withImages fills Images for vocabs returned to clients.
+func withImages(vs []models.Vocab) []models.Vocab {
+	ids := make([]string, len(vs))
+	for i, v := range vs {
+		ids[i] = v.ID
+	}
+	imgs := store.VocabImages(ids)
+	for i := range vs {
+		vs[i].Images = imgs[vs[i].ID]
+	}
+	return vs
+}
+
+// withVocabMedia fills image variants, the image list and audio of one vocab.
+func withVocabMedia(v models.Vocab) models.Vocab {
+	return withAudio(withImages(withImageVariants([]models.Vocab{v})))[0]
+}
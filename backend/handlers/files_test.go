@@ -0,0 +1,163 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"learnlang-backend/blob"
+	"learnlang-backend/router"
+)
+
+func TestImageVariant_GeneratedOnDemand(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("UPLOAD_DIR", dir)
+	if err := blob.Init(); err != nil {
+		t.Fatalf("blob init failed: %v", err)
+	}
+	h := router.NewRouter()
+
+	// An image stored before variants existed has no w160 copy yet.
+	if err := os.MkdirAll(filepath.Join(dir, "images"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "images", "old.png"), tinyPNG(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files/images/w160/old.png", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "images", "w160", "old.png")); err != nil {
+		t.Fatalf("expected cached variant: %v", err)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files/images/w999/old.png", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown width, got %d", w.Code)
+	}
+}
+
+// memStore is an in-memory, non-seekable blob store that can presign URLs.
+type memStore struct{ objects map[string][]byte }
+
+func (m *memStore) Put(_ context.Context, key string, data []byte, _ string) error {
+	m.objects[key] = data
+	return nil
+}
+
+func (m *memStore) Get(ctx context.Context, key string) (io.ReadCloser, blob.Info, error) {
+	info, err := m.Stat(ctx, key)
+	if err != nil {
+		return nil, info, err
+	}
+	return io.NopCloser(bytes.NewReader(m.objects[key])), info, nil
+}
+
+func (m *memStore) Stat(_ context.Context, key string) (blob.Info, error) {
+	data, ok := m.objects[key]
+	if !ok {
+		return blob.Info{}, blob.ErrNotFound
+	}
+	return blob.Info{Key: key, Size: int64(len(data)), ContentType: mime.TypeByExtension(path.Ext(key)), ETag: `"abc"`}, nil
+}
+
+func (m *memStore) Delete(_ context.Context, key string) error {
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *memStore) List(context.Context, string) ([]blob.Info, error) { return nil, nil }
+
+func (m *memStore) PresignGet(key string, _ time.Duration) (string, error) {
+	return "https://bucket.example/" + key + "?sig=1", nil
+}
+
+func TestFiles_ProxyAndPresignedRedirect(t *testing.T) {
+	t.Setenv("UPLOAD_DIR", t.TempDir())
+	blob.SetDefault(&memStore{objects: map[string][]byte{"images/cat.png": []byte("png-bytes"), "images/.upload-1": []byte("partial")}})
+	t.Cleanup(func() { blob.SetDefault(nil) })
+	h := router.NewRouter()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files/images/cat.png", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "png-bytes" {
+		t.Fatalf("proxy: got %d %q", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Content-Type") != "image/png" || w.Header().Get("ETag") != `"abc"` {
+		t.Fatalf("proxy headers: %v", w.Header())
+	}
+
+	// the backend's quoted ETag is echoed and matched as-is
+	req := httptest.NewRequest(http.MethodGet, "/files/images/cat.png", nil)
+	req.Header.Set("If-None-Match", `"abc"`)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("conditional: expected 304, got %d", w.Code)
+	}
+
+	for _, p := range []string{"/files/images/missing.png", "/files/images/.upload-1"} {
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, p, nil))
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("%s: expected 404, got %d", p, w.Code)
+		}
+	}
+
+	t.Setenv("FILES_MODE", "redirect")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files/images/cat.png", nil))
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "https://bucket.example/images/cat.png?sig=1" {
+		t.Fatalf("redirect: got %d %q", w.Code, w.Header().Get("Location"))
+	}
+}
+
+func TestFiles_CachingAndHardening(t *testing.T) {
+	t.Setenv("UPLOAD_DIR", t.TempDir())
+	hash := strings.Repeat("ab", 32)
+	blob.SetDefault(&memStore{objects: map[string][]byte{
+		"images/" + hash + ".png": []byte("png-bytes"),
+		"images/evil.svg":         []byte("<svg onload=alert(1)>"),
+	}})
+	t.Cleanup(func() { blob.SetDefault(nil) })
+	h := router.NewRouter()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files/images/"+hash+".png", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if cc := w.Header().Get("Cache-Control"); !strings.Contains(cc, "immutable") {
+		t.Errorf("Cache-Control = %q; want immutable", cc)
+	}
+	if w.Header().Get("ETag") != `"`+hash+`"` || w.Header().Get("X-Content-Type-Options") != "nosniff" {
+		t.Errorf("headers: %v", w.Header())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/files/images/"+hash+".png", nil)
+	req.Header.Set("If-None-Match", `"`+hash+`"`)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Fatalf("expected empty 304, got %d %q", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files/images/evil.svg", nil))
+	if w.Header().Get("Content-Type") != "application/octet-stream" || w.Header().Get("Content-Disposition") != "attachment" {
+		t.Fatalf("svg must not render inline: %v", w.Header())
+	}
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"learnlang-backend/models"
+	"learnlang-backend/store"
+	"learnlang-backend/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxVocabImages caps how many images a vocab may hold.
+const maxVocabImages = 10
+
+// AddVocabImagesRequestDTO adds images by reference: existing /files/images/
+// paths or http(s) URLs to fetch.
+type AddVocabImagesRequestDTO struct {
+	Images []string `json:"images"`
+}
+
+// SetVocabImagesRequestDTO replaces a vocab's image order. Attached images
+// left out are removed; primary defaults to the current primary if still
+// listed, else the first image.
+type SetVocabImagesRequestDTO struct {
+	Images  []string `json:"images"`
+	Primary string   `json:"primary"`
+}
+
+// AddVocabImagesHandler appends images to a vocab.
+// Accepts multipart/form-data with one or more "image" files, or JSON
+// {"images": [...]} with image references.
+func AddVocabImagesHandler(w http.ResponseWriter, r *http.Request) {
+	v, ok := vocabFromURL(w, r)
+	if !ok {
+		return
+	}
+	current := store.VocabImages([]string{v.ID})[v.ID]
+
+	var added []string
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		var req AddVocabImagesRequestDTO
+		if !decodeJSONBody(w, r, &req) {
+			return
+		}
+		if len(req.Images) == 0 {
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeMissingFields, "missing required field(s): images")
+			return
+		}
+		if len(current)+len(req.Images) > maxVocabImages {
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, fmt.Sprintf("too many images; max %d per vocab", maxVocabImages))
+			return
+		}
+		for _, ref := range req.Images {
+			u, uerr := resolveImageRef(r, strings.TrimSpace(ref))
+			if uerr != nil {
+				uerr.write(w, r)
+				return
+			}
+			added = append(added, u)
+		}
+	} else {
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidJSON, "multipart/form-data or application/json required")
+			return
+		}
+		headers := r.MultipartForm.File["image"]
+		if len(headers) == 0 {
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeMissingFields, "missing required field(s): image")
+			return
+		}
+		if len(current)+len(headers) > maxVocabImages {
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, fmt.Sprintf("too many images; max %d per vocab", maxVocabImages))
+			return
+		}
+		for _, h := range headers {
+			file, err := h.Open()
+			if err != nil {
+				utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidJSON, "invalid uploaded file")
+				return
+			}
+			img, uerr := saveImageUpload(w, file, h)
+			file.Close()
+			if uerr != nil {
+				uerr.write(w, r)
+				return
+			}
+			added = append(added, img.URL)
+		}
+	}
+
+	if err := store.AddVocabImages(v.ID, added); err != nil {
+		utils.WriteErrorWithRequest(w, r, http.StatusInternalServerError, utils.CodeInternal, "failed to add images")
+		return
+	}
+	utils.WriteOKData(w, withVocabMedia(v), nil)
+}
+
+// SetVocabImagesHandler reorders, removes and picks the primary image.
+func SetVocabImagesHandler(w http.ResponseWriter, r *http.Request) {
+	v, ok := vocabFromURL(w, r)
+	if !ok {
+		return
+	}
+	var req SetVocabImagesRequestDTO
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	if len(req.Images) == 0 {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeMissingFields, "missing required field(s): images; a vocab needs at least one image")
+		return
+	}
+	attached := map[string]bool{}
+	for _, p := range store.VocabImages([]string{v.ID})[v.ID] {
+		attached[p] = true
+	}
+	seen := map[string]bool{}
+	for i, p := range req.Images {
+		p = strings.TrimSpace(p)
+		if !attached[p] {
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, fmt.Sprintf("image not attached to vocab: %q", p))
+			return
+		}
+		if seen[p] {
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, fmt.Sprintf("duplicate image: %q", p))
+			return
+		}
+		seen[p] = true
+		req.Images[i] = p
+	}
+	primary := strings.TrimSpace(req.Primary)
+	switch {
+	case primary == "" && seen[v.Image]:
+		primary = v.Image
+	case primary == "":
+		primary = req.Images[0]
+	case !seen[primary]:
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, "primary must be one of images")
+		return
+	}
+
+	if err := store.SetVocabImages(v.ID, req.Images, primary); err != nil {
+		utils.WriteErrorWithRequest(w, r, http.StatusInternalServerError, utils.CodeInternal, "failed to update images")
+		return
+	}
+	v.Image = primary
+	utils.WriteOKData(w, withVocabMedia(v), nil)
+}
+
+// vocabFromURL loads the vocab named by the {id} URL parameter, writing a 404 if missing.
+func vocabFromURL(w http.ResponseWriter, r *http.Request) (models.Vocab, bool) {
+	id := strings.TrimSpace(chi.URLParam(r, "id"))
+	v, ok := store.GetVocabByID(id)
+	if !ok {
+		utils.WriteErrorWithRequest(w, r, http.StatusNotFound, utils.CodeInvalidVocab, fmt.Sprintf("unknown vocab id: %q", id))
+		return models.Vocab{}, false
+	}
+	return v, true
+}
@@ -0,0 +1,84 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"learnlang-backend/models"
+	"learnlang-backend/store"
+)
+
+func TestVocabImages_AddReorderPrimaryRemove(t *testing.T) {
+	h := setup(t)
+	imagesDir := filepath.Join(os.Getenv("UPLOAD_DIR"), "images")
+	if err := os.MkdirAll(imagesDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []string{"a.png", "b.png", "c.png"} {
+		if err := os.WriteFile(filepath.Join(imagesDir, n), tinyPNG(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store.CreatePack(models.Pack{ID: "p1", Name: "Kitchen", LangID: "1", UserID: "u1"}, "")
+	store.CreateVocab(models.Vocab{ID: "v1", Image: "/files/images/a.png", Name: "knife", Translation: "चाकू", PackID: "p1"}, "")
+
+	do := func(method string, body any) (int, models.Vocab) {
+		t.Helper()
+		b, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, "/api/vocabs/v1/images", bytes.NewReader(b))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		var resp struct {
+			Data models.Vocab `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Data
+	}
+
+	code, v := do(http.MethodPost, map[string]any{"images": []string{"/files/images/b.png", "/files/images/c.png"}})
+	if code != http.StatusOK || len(v.Images) != 3 || v.Images[0] != "/files/images/a.png" || v.Image != "/files/images/a.png" {
+		t.Fatalf("add: %d %+v", code, v)
+	}
+
+	// Reorder, drop a.png and make c.png primary.
+	code, v = do(http.MethodPut, map[string]any{"images": []string{"/files/images/c.png", "/files/images/b.png"}, "primary": "/files/images/c.png"})
+	if code != http.StatusOK || v.Image != "/files/images/c.png" || len(v.Images) != 2 || v.Images[1] != "/files/images/b.png" {
+		t.Fatalf("set: %d %+v", code, v)
+	}
+	if got, _ := store.GetVocabByID("v1"); got.Image != "/files/images/c.png" {
+		t.Fatalf("primary not persisted: %q", got.Image)
+	}
+
+	if code, _ := do(http.MethodPut, map[string]any{"images": []string{"/files/images/a.png"}}); code != http.StatusBadRequest {
+		t.Fatalf("detached image must be rejected, got %d", code)
+	}
+	if code, _ := do(http.MethodPut, map[string]any{"images": []string{}}); code != http.StatusBadRequest {
+		t.Fatalf("empty list must be rejected, got %d", code)
+	}
+
+	// Flashcards pick among the vocab's images.
+	seen := map[string]bool{}
+	for i := 0; i < 30; i++ {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/flashcards?user_id=u1&lang_id=1", nil))
+		var cards struct {
+			Data []struct {
+				Image string `json:"image"`
+			} `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &cards)
+		if len(cards.Data) != 1 {
+			t.Fatalf("expected 1 card, got %s", w.Body.String())
+		}
+		seen[cards.Data[0].Image] = true
+	}
+	if len(seen) != 2 || !seen["/files/images/b.png"] || !seen["/files/images/c.png"] {
+		t.Fatalf("expected both images across showings, saw %v", seen)
+	}
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"learnlang-backend/utils"
+)
+
+// decodeJSONBody decodes a single JSON object from the request body into dst,
+// rejecting unknown fields. On failure it writes a 400 error and returns false.
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, dst any) bool {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+
+		switch {
+		case errors.Is(err, io.EOF):
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeEmptyBody, "request body must not be empty")
+		case errors.As(err, &syntaxErr):
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeJSONSyntax, fmt.Sprintf("badly-formed JSON at position %d", syntaxErr.Offset))
+		case errors.As(err, &typeErr):
+			if typeErr.Field != "" {
+				utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeJSONType, fmt.Sprintf("invalid type for field %q: expected %s", typeErr.Field, typeErr.Type.String()))
+			} else {
+				utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeJSONType, fmt.Sprintf("invalid JSON value at position %d: expected %s", typeErr.Offset, typeErr.Type.String()))
+			}
+		case strings.HasPrefix(err.Error(), "json: unknown field "):
+			field := strings.TrimPrefix(err.Error(), "json: unknown field ")
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeUnknownField, fmt.Sprintf("unknown field %s", field))
+		case errors.Is(err, io.ErrUnexpectedEOF):
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeJSONSyntax, "badly-formed JSON")
+		default:
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidJSON, "invalid JSON payload")
+		}
+		return false
+	}
+
+	// Body must contain a single JSON object
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeMultipleObjects, "request body must contain a single JSON object")
+		return false
+	}
+	return true
+}
@@ -7,7 +7,28 @@ import (
 	"learnlang-backend/utils"
 )
 
-// GetLanguagesHandler returns the list of supported languages.
+// GetLanguagesHandler returns the list of enabled languages.
 func GetLanguagesHandler(w http.ResponseWriter, r *http.Request) {
-	utils.WriteOKData(w, store.LanguagesList(), nil)
+	utils.WriteOKDataCached(w, r, store.LanguagesList(), nil)
+}
+
+// languageIDExists reports whether id matches a known language. Disabled
+// languages count, since their packs remain listable.
+func languageIDExists(id string) bool {
+	for _, l := range store.AllLanguages() {
+		if l.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// languageCode returns the ISO code of a language ID, or "" if unknown.
+func languageCode(id string) string {
+	for _, l := range store.AllLanguages() {
+		if l.ID == id {
+			return l.Code
+		}
+	}
+	return ""
 }
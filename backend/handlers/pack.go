@@ -1,10 +1,7 @@
 package handlers
 
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 
@@ -20,50 +17,65 @@ import (
 // CreatePackRequestDTO is the request DTO for creating a new pack.
 // It intentionally omits ID to prevent clients from setting it.
 type CreatePackRequestDTO struct {
-	Name   string `json:"name"`
-	LangID string `json:"lang_id"`
-	UserID string `json:"user_id"`
+	Name         string `json:"name"`
+	LangID       string `json:"lang_id"`
+	SourceLangID string `json:"source_lang_id"` // optional; defaults to English
+	UserID       string `json:"user_id"`
+	UserName     string `json:"user_name"` // optional; owner display name in the public catalogue
+	Public       bool   `json:"public"`    // list the pack in the public catalogue
 }
 
-// GetPacksHandler returns all packs in the in-memory store.
+// GetPacksHandler returns all packs.
+// Optional query: lang_id, source_lang_id to list one language pair.
 func GetPacksHandler(w http.ResponseWriter, r *http.Request) {
-	packs := store.GetAllPacks()
-	utils.WriteOKData(w, packs, nil)
+	q := r.URL.Query()
+	f := store.PackFilter{
+		LangID:       strings.TrimSpace(q.Get("lang_id")),
+		SourceLangID: strings.TrimSpace(q.Get("source_lang_id")),
+	}
+	for _, id := range []string{f.LangID, f.SourceLangID} {
+		if id != "" && !languageIDExists(id) {
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidLanguage, fmt.Sprintf("unsupported language id: %q", id))
+			return
+		}
+	}
+	packs := store.ListPacks(f)
+	utils.WriteOKDataCached(w, r, packs, nil)
 }
 
-func CreatePackHandler(w http.ResponseWriter, r *http.Request) {
-	var req CreatePackRequestDTO
-	dec := json.NewDecoder(r.Body)
-	dec.DisallowUnknownFields()
-	if err := dec.Decode(&req); err != nil {
-		var syntaxErr *json.SyntaxError
-		var typeErr *json.UnmarshalTypeError
-
-		switch {
-		case errors.Is(err, io.EOF):
-			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeEmptyBody, "request body must not be empty")
-		case errors.As(err, &syntaxErr):
-			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeJSONSyntax, fmt.Sprintf("badly-formed JSON at position %d", syntaxErr.Offset))
-		case errors.As(err, &typeErr):
-			if typeErr.Field != "" {
-				utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeJSONType, fmt.Sprintf("invalid type for field %q: expected %s", typeErr.Field, typeErr.Type.String()))
-			} else {
-				utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeJSONType, fmt.Sprintf("invalid JSON value at position %d: expected %s", typeErr.Offset, typeErr.Type.String()))
-			}
-		case strings.HasPrefix(err.Error(), "json: unknown field "):
-			field := strings.TrimPrefix(err.Error(), "json: unknown field ")
-			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeUnknownField, fmt.Sprintf("unknown field %s", field))
-		case errors.Is(err, io.ErrUnexpectedEOF):
-			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeJSONSyntax, "badly-formed JSON")
-		default:
-			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidJSON, "invalid JSON payload")
+// GetPublicPacksHandler returns the public pack catalogue.
+// Optional query: q (name search), lang_id, source_lang_id, sort=popular|recent|name, limit, offset.
+func GetPublicPacksHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := store.PublicPackFilter{
+		Query:        strings.TrimSpace(q.Get("q")),
+		LangID:       strings.TrimSpace(q.Get("lang_id")),
+		SourceLangID: strings.TrimSpace(q.Get("source_lang_id")),
+		Sort:         strings.TrimSpace(q.Get("sort")),
+	}
+	for _, id := range []string{f.LangID, f.SourceLangID} {
+		if id != "" && !languageIDExists(id) {
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidLanguage, fmt.Sprintf("unsupported language id: %q", id))
+			return
 		}
+	}
+	switch f.Sort {
+	case "":
+		f.Sort = store.SortPopular
+	case store.SortPopular, store.SortRecent, store.SortName:
+	default:
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, fmt.Sprintf("unsupported sort: %q", f.Sort))
 		return
 	}
+	f.Limit, f.Offset = parsePaging(q, 20, 100)
 
-	// Body must contain a single JSON object
-	if err := dec.Decode(&struct{}{}); err != io.EOF {
-		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeMultipleObjects, "request body must contain a single JSON object")
+	packs := store.ListPublicPacks(f)
+	utils.WriteOKDataCached(w, r, packs, map[string]any{"count": len(packs), "limit": f.Limit, "offset": f.Offset, "sort": f.Sort})
+}
+
+func CreatePackHandler(w http.ResponseWriter, r *http.Request) {
+	var req CreatePackRequestDTO
+	if !decodeJSONBody(w, r, &req) {
 		return
 	}
 
@@ -83,57 +95,105 @@ func CreatePackHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if language exists and get its ID
-	// Validate language by ID
+	// Packs without a source language default to English, if it is enabled.
+	if req.SourceLangID == "" {
+		l, ok := store.GetLanguageByCode(store.DefaultSourceLangCode)
+		if !ok || !l.Enabled {
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeMissingFields,
+				fmt.Sprintf("missing required field(s): source_lang_id (default source language %q is not available)", store.DefaultSourceLangCode))
+			return
+		}
+		req.SourceLangID = l.ID
+	}
 	langs := store.LanguagesList()
-	var found bool
-	for _, l := range langs {
-		if l.ID == req.LangID {
-			found = true
-			break
+	for _, id := range []string{req.LangID, req.SourceLangID} {
+		var found bool
+		for _, l := range langs {
+			if l.ID == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidLanguage, fmt.Sprintf("unsupported language id: %q", id))
+			return
 		}
 	}
-	if !found {
-		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidLanguage, fmt.Sprintf("unsupported language id: %q", req.LangID))
+	if req.SourceLangID == req.LangID {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidLanguage, "source_lang_id must differ from lang_id")
 		return
 	}
 
 	// Check uniqueness
-	key := utils.MakePackKey(req.UserID, req.LangID, req.Name)
+	key := utils.MakePackKey(req.UserID, req.LangID, req.SourceLangID, req.Name)
 	if store.PackExistsByKey(key) {
-		utils.WriteErrorWithRequest(w, r, http.StatusConflict, utils.CodeDuplicatePack, fmt.Sprintf("pack %q already exists for user %q and language %q", req.Name, req.UserID, req.LangID))
+		utils.WriteErrorWithRequest(w, r, http.StatusConflict, utils.CodeDuplicatePack,
+			fmt.Sprintf("pack %q already exists for user %q and languages %q/%q", req.Name, req.UserID, req.LangID, req.SourceLangID))
 		return
 	}
 
 	// Create and store pack
 	pack := models.Pack{
-		ID:     uuid.New().String(),
-		Name:   req.Name,
-		LangID: req.LangID,
-		UserID: req.UserID,
+		ID:           uuid.New().String(),
+		Name:         req.Name,
+		LangID:       req.LangID,
+		SourceLangID: req.SourceLangID,
+		UserID:       req.UserID,
+		Public:       req.Public,
+	}
+	if name := strings.TrimSpace(req.UserName); name != "" {
+		if err := store.SaveUserName(req.UserID, name); err != nil {
+			utils.WriteErrorWithRequest(w, r, http.StatusInternalServerError, utils.CodeInternal, "failed to save user name")
+			return
+		}
 	}
 	store.CreatePack(pack, key)
 
 	utils.WriteCreatedData(w, pack, nil)
 }
 
-// GetPackByIDHandler returns a single pack by its ID.
+// GetPackByIDHandler returns a single pack by its ID with its vocabs.
+// Optional query: sort=name|translation|created|difficulty (default name),
+// order=asc|desc. Names and translations sort by their language's collation.
 func GetPackByIDHandler(w http.ResponseWriter, r *http.Request) {
 	id := strings.TrimSpace(chi.URLParam(r, "id"))
 	if id == "" {
 		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidPack, "missing pack id")
 		return
 	}
+	q := r.URL.Query()
+	order := store.VocabSort{Key: strings.TrimSpace(q.Get("sort"))}
+	switch order.Key {
+	case "":
+		order.Key = store.VocabSortName
+	case store.VocabSortName, store.VocabSortTranslation, store.VocabSortCreated, store.VocabSortDifficulty:
+	default:
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, fmt.Sprintf("unsupported sort: %q", order.Key))
+		return
+	}
+	switch dir := strings.TrimSpace(q.Get("order")); dir {
+	case "", "asc":
+	case "desc":
+		order.Desc = true
+	default:
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, fmt.Sprintf("unsupported order: %q", dir))
+		return
+	}
 	p, ok := store.GetPackByID(id)
 	if !ok {
 		utils.WriteErrorWithRequest(w, r, http.StatusNotFound, utils.CodeInvalidPack, fmt.Sprintf("unknown pack id: %q", id))
 		return
 	}
 	// Fetch related vocabs for this pack (user/lang implied by pack)
-	vocabs := store.ListVocabsByPackID(p.ID)
+	vocabs := withAudio(withImages(withImageVariants(store.ListVocabsByPackID(p.ID, order))))
+	// The languages tell clients how to render names and translations (script, direction)
+	lang, _ := store.GetLanguageByID(p.LangID)
+	source, _ := store.GetLanguageByID(p.SourceLangID)
 	type response struct {
-		Pack   models.Pack    `json:"pack"`
-		Vocabs []models.Vocab `json:"vocabs"`
+		Pack           models.Pack     `json:"pack"`
+		Language       models.Language `json:"language"`
+		SourceLanguage models.Language `json:"source_language"`
+		Vocabs         []models.Vocab  `json:"vocabs"`
 	}
-	utils.WriteOKData(w, response{Pack: p, Vocabs: vocabs}, nil)
+	utils.WriteOKDataCached(w, r, response{Pack: p, Language: lang, SourceLanguage: source, Vocabs: vocabs}, nil)
 }
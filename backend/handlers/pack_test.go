@@ -6,8 +6,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
+	"learnlang-backend/blob"
+	"learnlang-backend/models"
 	"learnlang-backend/router"
 	"learnlang-backend/store"
 )
@@ -22,6 +25,9 @@ func setup(t *testing.T) http.Handler {
 	t.Helper()
 	// Ensure uploads dir env is set for router/static and handlers
 	os.Setenv("UPLOAD_DIR", t.TempDir())
+	if err := blob.Init(); err != nil {
+		t.Fatalf("blob init failed: %v", err)
+	}
 	if err := store.InitFromEnv(); err != nil {
 		t.Fatalf("db init failed: %v", err)
 	}
@@ -93,3 +99,178 @@ func TestGetLanguages_OK(t *testing.T) {
 		t.Fatalf("expected languages, got none")
 	}
 }
+
+func TestGetPublicPacks_OnlyPublic(t *testing.T) {
+	h := setup(t)
+
+	store.CreatePack(models.Pack{ID: "pub-1", Name: "Farm Animals", LangID: "1", UserID: "u1", Public: true}, "")
+	store.CreatePack(models.Pack{ID: "pub-2", Name: "Kitchen", LangID: "2", UserID: "u2", Public: true}, "")
+	store.CreatePack(models.Pack{ID: "priv-1", Name: "Farm Secrets", LangID: "1", UserID: "u1"}, "")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/public/packs?q=farm&lang_id=1", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data []map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(resp.Data) != 1 || resp.Data[0]["id"] != "pub-1" {
+		t.Fatalf("expected only pub-1, got %#v", resp.Data)
+	}
+	if resp.Data[0]["owner_name"] != "u1" {
+		t.Fatalf("expected owner_name fallback to user id, got %v", resp.Data[0]["owner_name"])
+	}
+}
+
+func TestCreatePack_PublicWithOwnerName(t *testing.T) {
+	h := setup(t)
+
+	body, _ := json.Marshal(map[string]any{"name": "Basics", "lang_id": "1", "user_id": "u1", "user_name": "Asha", "public": true})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/packs", bytes.NewReader(body)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/public/packs", nil))
+	var resp struct {
+		Data []models.PublicPack `json:"data"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp.Data) != 1 || resp.Data[0].Name != "Basics" || resp.Data[0].OwnerName != "Asha" {
+		t.Fatalf("expected public pack owned by Asha, got %+v", resp.Data)
+	}
+
+	// a later create naming the same user_id does not rename them
+	body, _ = json.Marshal(map[string]any{"name": "More", "lang_id": "1", "user_id": "u1", "user_name": "Mallory"})
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/packs", bytes.NewReader(body)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/public/packs", nil))
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp.Data) != 1 || resp.Data[0].OwnerName != "Asha" {
+		t.Fatalf("expected owner name to stay Asha, got %+v", resp.Data)
+	}
+}
+
+func TestGetPublicPacks_InvalidSort(t *testing.T) {
+	h := setup(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/public/packs?sort=random", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestCreatePack_LanguagePair(t *testing.T) {
+	h := setup(t)
+
+	post := func(body map[string]string) (int, map[string]any) {
+		t.Helper()
+		b, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/packs", bytes.NewReader(b)))
+		var resp struct {
+			Data map[string]any `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Data
+	}
+	if code, p := post(map[string]string{"name": "Küche", "lang_id": "1", "source_lang_id": "2", "user_id": "u1"}); code != http.StatusCreated || p["source_lang_id"] != "2" {
+		t.Fatalf("expected Hindi pack with German translations, got %d %v", code, p)
+	}
+	en, _ := store.GetLanguageByCode("en")
+	if code, p := post(map[string]string{"name": "Kitchen", "lang_id": "2", "user_id": "u1"}); code != http.StatusCreated || p["source_lang_id"] != en.ID {
+		t.Fatalf("expected source language to default to English, got %d %v", code, p)
+	}
+	if code, _ := post(map[string]string{"name": "Same", "lang_id": "1", "source_lang_id": "1", "user_id": "u1"}); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for identical languages, got %d", code)
+	}
+	if code, _ := post(map[string]string{"name": "Unknown", "lang_id": "1", "source_lang_id": "nope", "user_id": "u1"}); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown source language, got %d", code)
+	}
+	// The same name may be reused for another language pair, not the same one.
+	if code, _ := post(map[string]string{"name": "Küche", "lang_id": "1", "source_lang_id": en.ID, "user_id": "u1"}); code != http.StatusCreated {
+		t.Fatalf("expected same name with another source language to be created, got %d", code)
+	}
+	if code, _ := post(map[string]string{"name": "Küche", "lang_id": "1", "source_lang_id": "2", "user_id": "u1"}); code != http.StatusConflict {
+		t.Fatalf("expected 409 for a duplicate in the same pair, got %d", code)
+	}
+	en.Enabled = false
+	if err := store.UpdateLanguage(en); err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := post(map[string]string{"name": "Garten", "lang_id": "2", "user_id": "u1"}); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 asking for source_lang_id when English is disabled, got %d", code)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/packs?lang_id=1&source_lang_id=2", nil))
+	var resp struct {
+		Data []models.Pack `json:"data"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if w.Code != http.StatusOK || len(resp.Data) != 1 || resp.Data[0].Name != "Küche" {
+		t.Fatalf("expected only the hi/de pack, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGetPackByID_Sorted(t *testing.T) {
+	h := setup(t)
+	store.CreatePack(models.Pack{ID: "p1", Name: "Obst", LangID: "2", UserID: "u1"}, "")
+	store.CreateVocab(models.Vocab{ID: "v1", Image: "/files/images/a.png", Name: "Zitrone", Translation: "lemon", PackID: "p1", Difficulty: 2}, "")
+	store.CreateVocab(models.Vocab{ID: "v2", Image: "/files/images/b.png", Name: "Äpfel", Translation: "apples", PackID: "p1"}, "")
+	store.CreateVocab(models.Vocab{ID: "v3", Image: "/files/images/c.png", Name: "Birne", Translation: "pear", PackID: "p1", Difficulty: 4}, "")
+
+	names := func(query string) []string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/packs/p1"+query, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d body=%s", query, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				Vocabs []models.Vocab `json:"vocabs"`
+			} `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		var out []string
+		for _, v := range resp.Data.Vocabs {
+			out = append(out, v.Name)
+		}
+		return out
+	}
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", "Äpfel Birne Zitrone"}, // byte order would put Äpfel last
+		{"?sort=name&order=desc", "Zitrone Birne Äpfel"},
+		{"?sort=translation", "Äpfel Zitrone Birne"},
+		{"?sort=difficulty", "Zitrone Birne Äpfel"},            // unrated last
+		{"?sort=difficulty&order=desc", "Birne Zitrone Äpfel"}, // unrated still last
+	}
+	for _, tt := range tests {
+		if got := strings.Join(names(tt.query), " "); got != tt.want {
+			t.Errorf("%q: got %s; want %s", tt.query, got, tt.want)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/packs/p1?sort=random", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown sort, got %d", w.Code)
+	}
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// parsePaging reads limit and offset query params. Invalid values fall back
+// to defaults; limit is capped at maxLimit.
+func parsePaging(q url.Values, defLimit, maxLimit int) (limit, offset int) {
+	limit = defLimit
+	if s := strings.TrimSpace(q.Get("limit")); s != "" {
+		if n, err := strconv.Atoi(s); err == nil && n > 0 {
+			if n > maxLimit {
+				n = maxLimit
+			}
+			limit = n
+		}
+	}
+	if s := strings.TrimSpace(q.Get("offset")); s != "" {
+		if n, err := strconv.Atoi(s); err == nil && n > 0 {
+			offset = n
+		}
+	}
+	return limit, offset
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"learnlang-backend/models"
+	"learnlang-backend/store"
+	"learnlang-backend/utils"
+)
+
+// Quiz modes accepted by GetFlashcardsHandler and CheckAnswerHandler.
+const (
+	quizModeTranslation = "translation"
+	quizModeName        = "name" // reverse: answer with the name or a synonym
+	quizModeArticle     = "article"
+)
+
+// CheckAnswerRequestDTO is a learner's answer to a flashcard.
+type CheckAnswerRequestDTO struct {
+	VocabID string `json:"vocab_id"`
+	Mode    string `json:"mode"` // "translation" (default), "name" or "article"
+	Answer  string `json:"answer"`
+}
+
+// CheckAnswerResult tells whether an answer was right and what was expected.
+type CheckAnswerResult struct {
+	Correct  bool   `json:"correct"`
+	Expected string `json:"expected"`
+}
+
+// CheckAnswerHandler grades an answer to a flashcard. In translation mode any
+// accepted translation is right; in name mode the name or a synonym; in
+// article mode the definite article of the vocab's gender. Comparison ignores
+// case and extra spaces, and Devanagari answers may also be typed romanised
+// (IAST, ITRANS or the vocab's own transliteration).
+func CheckAnswerHandler(w http.ResponseWriter, r *http.Request) {
+	var req CheckAnswerRequestDTO
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	req.VocabID = strings.TrimSpace(req.VocabID)
+	if req.VocabID == "" {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeMissingFields, "missing required field(s): vocab_id")
+		return
+	}
+	v, ok := store.GetVocabByID(req.VocabID)
+	if !ok {
+		utils.WriteErrorWithRequest(w, r, http.StatusNotFound, utils.CodeInvalidVocab, fmt.Sprintf("unknown vocab id: %q", req.VocabID))
+		return
+	}
+
+	var (
+		expected string
+		accepted []string // answers counted as correct, expected included
+	)
+	switch req.Mode {
+	case "", quizModeTranslation:
+		expected = v.Translation
+		accepted = v.Translations
+	case quizModeName:
+		expected = v.Name
+		accepted = append([]string{v.Name}, v.Synonyms...)
+	case quizModeArticle:
+		pack, _ := store.GetPackByID(v.PackID)
+		article, ok := utils.DefiniteArticle(languageCode(pack.LangID), v.Grammar)
+		if !ok {
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, "vocab has no gender to quiz the article of")
+			return
+		}
+		expected = article
+	default:
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, fmt.Sprintf("unsupported mode: %q", req.Mode))
+		return
+	}
+	if len(accepted) == 0 {
+		accepted = []string{expected}
+	}
+
+	var correct bool
+	for _, a := range accepted {
+		if utils.AnswerMatches(req.Answer, a) || utils.HasDevanagari(a) && utils.RomanizedMatches(req.Answer, romanizations(v, a)...) {
+			correct = true
+			break
+		}
+	}
+	utils.WriteOKData(w, CheckAnswerResult{Correct: correct, Expected: expected}, nil)
+}
+
+// romanizations lists the accepted romanised spellings of Devanagari text:
+// both built-in schemes, plus the vocab's own transliteration if made from it.
+func romanizations(v models.Vocab, text string) []string {
+	out := []string{utils.Transliterate(text, utils.SchemeIAST), utils.Transliterate(text, utils.SchemeITRANS)}
+	if utils.TransliterationSource(v) == text && v.Transliteration != "" {
+		out = append(out, v.Transliteration)
+	}
+	return out
+}
@@ -0,0 +1,184 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"learnlang-backend/models"
+	"learnlang-backend/store"
+)
+
+func TestArticleQuiz(t *testing.T) {
+	h := setup(t)
+	store.CreatePack(models.Pack{ID: "p1", Name: "Küche", LangID: "2", UserID: "u1"}, "")
+	store.CreateVocab(models.Vocab{ID: "v1", Image: "/files/images/a.png", Name: "Messer", Translation: "knife", PackID: "p1",
+		PartOfSpeech: "noun", Grammar: &models.Grammar{Gender: "neuter", Plural: "Messer"}}, "")
+	store.CreateVocab(models.Vocab{ID: "v2", Image: "/files/images/b.png", Name: "schneiden", Translation: "to cut", PackID: "p1"}, "")
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/flashcards?user_id=u1&lang_id=2&mode=article", nil))
+	var cards struct {
+		Data []struct {
+			ID      string   `json:"id"`
+			Mode    string   `json:"mode"`
+			Choices []string `json:"choices"`
+		} `json:"data"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &cards)
+	if w.Code != http.StatusOK || len(cards.Data) != 1 || cards.Data[0].ID != "v1" || cards.Data[0].Mode != "article" || len(cards.Data[0].Choices) != 3 {
+		t.Fatalf("expected one article card for v1, got %d %s", w.Code, w.Body.String())
+	}
+
+	check := func(body map[string]string) (int, map[string]any) {
+		t.Helper()
+		b, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, "/api/flashcards/check", bytes.NewReader(b))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		var resp struct {
+			Data map[string]any `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Data
+	}
+	if code, res := check(map[string]string{"vocab_id": "v1", "mode": "article", "answer": "Das"}); code != http.StatusOK || res["correct"] != true {
+		t.Fatalf("expected correct article, got %d %v", code, res)
+	}
+	if code, res := check(map[string]string{"vocab_id": "v1", "mode": "article", "answer": "der"}); code != http.StatusOK || res["correct"] != false || res["expected"] != "das" {
+		t.Fatalf("expected wrong article, got %d %v", code, res)
+	}
+	if code, _ := check(map[string]string{"vocab_id": "v2", "mode": "article", "answer": "der"}); code != http.StatusBadRequest {
+		t.Fatalf("vocab without gender must be rejected, got %d", code)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/flashcards?user_id=u1&lang_id=1&mode=article", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("article quiz needs a language with articles, got %d", w.Code)
+	}
+}
+
+func TestNameQuiz_HidesAnswer(t *testing.T) {
+	h := setup(t)
+	store.CreatePack(models.Pack{ID: "p1", Name: "Küche", LangID: "2", UserID: "u1"}, "")
+	store.CreateVocab(models.Vocab{ID: "v1", Image: "/files/images/a.png", Name: "Messer", Translation: "knife", PackID: "p1",
+		Transliteration: "messer"}, "")
+	if err := store.SaveTTSAudio("de", "Messer", "/files/audio/messer.wav"); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/flashcards?user_id=u1&lang_id=2&mode=name", nil))
+	var cards struct {
+		Data []map[string]any `json:"data"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &cards)
+	if w.Code != http.StatusOK || len(cards.Data) != 1 || cards.Data[0]["translation"] != "knife" {
+		t.Fatalf("expected one name card prompting with the translation, got %d %s", w.Code, w.Body.String())
+	}
+	for _, field := range []string{"name", "transliteration", "audio"} {
+		if v, ok := cards.Data[0][field]; ok {
+			t.Errorf("name card must not reveal %s, got %v", field, v)
+		}
+	}
+}
+
+func TestCheckAnswer_RomanizedHindi(t *testing.T) {
+	h := setup(t)
+	store.CreatePack(models.Pack{ID: "p1", Name: "Kitchen", LangID: "1", UserID: "u1"}, "")
+	store.CreateVocab(models.Vocab{ID: "v1", Image: "/files/images/a.png", Name: "knife", Translation: "चाकू", PackID: "p1"}, "")
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/flashcards?user_id=u1&lang_id=1", nil))
+	var cards struct {
+		Data []struct {
+			Transliteration string `json:"transliteration"`
+		} `json:"data"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &cards)
+	if len(cards.Data) != 1 || cards.Data[0].Transliteration != "cākū" {
+		t.Fatalf("expected generated transliteration, got %s", w.Body.String())
+	}
+
+	for answer, want := range map[string]bool{"चाकू": true, "chaakuu": true, "caku": true, "kanta": false} {
+		b, _ := json.Marshal(map[string]string{"vocab_id": "v1", "answer": answer})
+		req := httptest.NewRequest(http.MethodPost, "/api/flashcards/check", bytes.NewReader(b))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		var resp struct {
+			Data struct {
+				Correct bool `json:"correct"`
+			} `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		if w.Code != http.StatusOK || resp.Data.Correct != want {
+			t.Errorf("answer %q: got %d correct=%v; want %v", answer, w.Code, resp.Data.Correct, want)
+		}
+	}
+}
+
+func TestCheckAnswer_TranslationsAndSynonyms(t *testing.T) {
+	h := setup(t)
+	store.CreatePack(models.Pack{ID: "p1", Name: "Verkehr", LangID: "2", UserID: "u1"}, "")
+	imagesDir := filepath.Join(os.Getenv("UPLOAD_DIR"), "images")
+	if err := os.MkdirAll(imagesDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(imagesDir, "car.png"), tinyPNG(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	body, _ := json.Marshal(map[string]any{"name": "Auto", "pack_id": "p1", "image": "/files/images/car.png",
+		"translations": []string{"car", "automobile"}, "synonyms": []string{"Wagen"}})
+	req := httptest.NewRequest(http.MethodPost, "/api/vocabs", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var created struct {
+		Data models.Vocab `json:"data"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+	if w.Code != http.StatusCreated || created.Data.Translation != "car" || len(created.Data.Translations) != 2 {
+		t.Fatalf("create: %d %s", w.Code, w.Body.String())
+	}
+	id := created.Data.ID
+
+	for _, tc := range []struct {
+		mode, answer string
+		want         bool
+	}{
+		{"translation", "car", true},
+		{"translation", "Automobile", true},
+		{"translation", "bus", false},
+		{"name", "auto", true},
+		{"name", "wagen", true},
+	} {
+		b, _ := json.Marshal(map[string]string{"vocab_id": id, "mode": tc.mode, "answer": tc.answer})
+		req := httptest.NewRequest(http.MethodPost, "/api/flashcards/check", bytes.NewReader(b))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		var resp struct {
+			Data struct {
+				Correct bool `json:"correct"`
+			} `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		if w.Code != http.StatusOK || resp.Data.Correct != tc.want {
+			t.Errorf("%s %q: got %d correct=%v; want %v", tc.mode, tc.answer, w.Code, resp.Data.Correct, tc.want)
+		}
+	}
+
+	sw := httptest.NewRecorder()
+	h.ServeHTTP(sw, httptest.NewRequest(http.MethodGet, "/api/search?q=automobile&user_id=u1", nil))
+	if !strings.Contains(sw.Body.String(), id) {
+		t.Fatalf("search by alternate translation: %s", sw.Body.String())
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"learnlang-backend/store"
+	"learnlang-backend/utils"
+)
+
+// SearchVocabsHandler searches vocab names and translations across a user's packs.
+// Required query: q, user_id. Optional: lang_id, public=true to include public packs,
+// tag, pos (part of speech), limit. Notes, tags and example sentences are searched too.
+func SearchVocabsHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := store.VocabSearchFilter{
+		Query:        strings.TrimSpace(q.Get("q")),
+		UserID:       strings.TrimSpace(q.Get("user_id")),
+		LangID:       strings.TrimSpace(q.Get("lang_id")),
+		Tag:          strings.ToLower(strings.TrimSpace(q.Get("tag"))),
+		PartOfSpeech: strings.ToLower(strings.TrimSpace(q.Get("pos"))),
+	}
+	if f.Query == "" || f.UserID == "" {
+		miss := make([]string, 0, 2)
+		if f.Query == "" {
+			miss = append(miss, "q")
+		}
+		if f.UserID == "" {
+			miss = append(miss, "user_id")
+		}
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeMissingFields, fmt.Sprintf("missing required query param(s): %s", strings.Join(miss, ", ")))
+		return
+	}
+	if f.LangID != "" && !languageIDExists(f.LangID) {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidLanguage, fmt.Sprintf("unsupported language id: %q", f.LangID))
+		return
+	}
+	if s := strings.TrimSpace(q.Get("public")); s != "" {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, fmt.Sprintf("invalid public flag: %q", s))
+			return
+		}
+		f.IncludePublic = b
+	}
+	f.Limit, _ = parsePaging(q, 20, 100)
+
+	results := store.SearchVocabs(f)
+	for i := range results {
+		results[i].ImageVariants = utils.ImageVariants(results[i].Image)
+	}
+	utils.WriteOKDataCached(w, r, results, map[string]any{"count": len(results)})
+}
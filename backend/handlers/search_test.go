@@ -0,0 +1,47 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"learnlang-backend/models"
+	"learnlang-backend/store"
+)
+
+func TestSearchVocabs_AccentInsensitiveAndScoped(t *testing.T) {
+	h := setup(t)
+
+	store.CreatePack(models.Pack{ID: "p-own", Name: "People", LangID: "2", UserID: "u1"}, "")
+	store.CreatePack(models.Pack{ID: "p-other", Name: "Other", LangID: "2", UserID: "u2"}, "")
+	store.CreateVocab(models.Vocab{ID: "v1", Image: "/files/images/a.png", Name: "Müller", Translation: "miller", PackID: "p-own"}, "")
+	store.CreateVocab(models.Vocab{ID: "v2", Image: "/files/images/b.png", Name: "Müller", Translation: "miller", PackID: "p-other"}, "")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search?q=muller&user_id=u1&lang_id=2", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data []map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(resp.Data) != 1 || resp.Data[0]["id"] != "v1" || resp.Data[0]["pack_name"] != "People" {
+		t.Fatalf("expected only v1 from own pack, got %#v", resp.Data)
+	}
+}
+
+func TestSearchVocabs_MissingQuery(t *testing.T) {
+	h := setup(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search?user_id=u1", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"learnlang-backend/speech"
+	"learnlang-backend/store"
+	"learnlang-backend/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GetPronunciationHandler returns synthesized pronunciation audio for a vocab's
+// name in its pack's language, generating and caching it on first request.
+func GetPronunciationHandler(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSpace(chi.URLParam(r, "id"))
+	if _, ok := store.GetVocabByID(id); !ok {
+		utils.WriteErrorWithRequest(w, r, http.StatusNotFound, utils.CodeInvalidVocab, fmt.Sprintf("unknown vocab id: %q", id))
+		return
+	}
+	p := speech.Default()
+	if p == nil {
+		utils.WriteErrorWithRequest(w, r, http.StatusServiceUnavailable, utils.CodeTTSUnavailable, "text-to-speech is not configured")
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	url, err := speech.PronounceVocab(ctx, p, id)
+	if err != nil {
+		log.Printf("tts for vocab %s: %v", id, err)
+		utils.WriteErrorWithRequest(w, r, http.StatusBadGateway, utils.CodeTTSFailed, "failed to synthesize pronunciation")
+		return
+	}
+	utils.WriteOKData(w, map[string]string{"vocab_id": id, "audio": url}, map[string]any{"source": "tts"})
+}
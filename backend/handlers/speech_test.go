@@ -0,0 +1,69 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"learnlang-backend/models"
+	"learnlang-backend/speech"
+	"learnlang-backend/store"
+)
+
+func TestPronunciation_GeneratedOnceAndUsedByFlashcards(t *testing.T) {
+	h := setup(t)
+	fake := &speech.Fake{}
+	speech.SetDefault(fake)
+	t.Cleanup(func() { speech.SetDefault(nil) })
+
+	store.CreatePack(models.Pack{ID: "p1", Name: "Kitchen", LangID: "1", UserID: "u1"}, "")
+	store.CreateVocab(models.Vocab{ID: "v1", Image: "/files/images/a.png", Name: "चाकू", Translation: "knife", PackID: "p1"}, "")
+
+	var url string
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/vocabs/v1/pronunciation", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				Audio string `json:"audio"`
+			} `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		if !strings.HasPrefix(resp.Data.Audio, "/files/audio/") || (url != "" && resp.Data.Audio != url) {
+			t.Fatalf("unexpected audio url %q", resp.Data.Audio)
+		}
+		url = resp.Data.Audio
+	}
+	lang := store.LanguagesList()
+	var code string
+	for _, l := range lang {
+		if l.ID == "1" {
+			code = l.Code
+		}
+	}
+	if fake.CallCount() != 1 || fake.Calls[0] != code+":चाकू" {
+		t.Fatalf("expected one synthesis for %s, got %v", code, fake.Calls)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/flashcards?user_id=u1&lang_id=1", nil))
+	var cards struct {
+		Data []map[string]any `json:"data"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &cards)
+	if len(cards.Data) != 1 || cards.Data[0]["audio"] != url {
+		t.Fatalf("expected flashcard to fall back to TTS audio, got %v", cards.Data)
+	}
+
+	speech.SetDefault(nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/vocabs/v1/pronunciation", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without provider, got %d", w.Code)
+	}
+}
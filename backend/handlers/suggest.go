@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"learnlang-backend/models"
+	"learnlang-backend/store"
+	"learnlang-backend/translate"
+	"learnlang-backend/utils"
+)
+
+// SuggestTranslationHandler suggests translations for a word being added.
+// Required query: text, from, to (language IDs or codes, e.g. from=2&to=1 or
+// from=de&to=hi).
+func SuggestTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	text := strings.TrimSpace(q.Get("text"))
+	fromParam, toParam := strings.TrimSpace(q.Get("from")), strings.TrimSpace(q.Get("to"))
+	missing := make([]string, 0, 3)
+	for _, p := range []struct{ name, value string }{{"text", text}, {"from", fromParam}, {"to", toParam}} {
+		if p.value == "" {
+			missing = append(missing, p.name)
+		}
+	}
+	if len(missing) > 0 {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeMissingFields, fmt.Sprintf("missing required query param(s): %s", strings.Join(missing, ", ")))
+		return
+	}
+	if utf8.RuneCountInString(text) > utils.MaxTranslationLen {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, fmt.Sprintf("text too long; max %d characters", utils.MaxTranslationLen))
+		return
+	}
+	from, ok := resolveLanguage(fromParam)
+	if !ok {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidLanguage, fmt.Sprintf("unsupported language: %q", fromParam))
+		return
+	}
+	to, ok := resolveLanguage(toParam)
+	if !ok {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidLanguage, fmt.Sprintf("unsupported language: %q", toParam))
+		return
+	}
+	if from.Code == to.Code {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidLanguage, "from and to must differ")
+		return
+	}
+	t := translate.Default()
+	if t == nil {
+		utils.WriteErrorWithRequest(w, r, http.StatusServiceUnavailable, utils.CodeTranslateUnavailable, "translation suggestions are not configured")
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+	suggestions, cached, err := translate.Suggest(ctx, t, text, from.Code, to.Code)
+	if err != nil {
+		log.Printf("translate %q %s->%s: %v", text, from.Code, to.Code, err)
+		utils.WriteErrorWithRequest(w, r, http.StatusBadGateway, utils.CodeTranslateFailed, "failed to fetch translation suggestions")
+		return
+	}
+	source := "translator"
+	if cached {
+		source = "cache"
+	}
+	utils.WriteOKData(w, map[string]any{"text": text, "from": from.Code, "to": to.Code, "suggestions": suggestions},
+		map[string]any{"count": len(suggestions), "source": source})
+}
+
+// resolveLanguage finds a language by ID or, failing that, by code.
+// Disabled languages are included.
+func resolveLanguage(idOrCode string) (models.Language, bool) {
+	langs := store.AllLanguages()
+	for _, l := range langs {
+		if l.ID == idOrCode {
+			return l, true
+		}
+	}
+	for _, l := range langs {
+		if strings.EqualFold(l.Code, idOrCode) {
+			return l, true
+		}
+	}
+	return models.Language{}, false
+}
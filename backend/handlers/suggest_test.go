@@ -0,0 +1,143 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"learnlang-backend/dictionary"
+	"learnlang-backend/translate"
+)
+
+func TestSuggestTranslation_CachedByTextAndProvider(t *testing.T) {
+	h := setup(t)
+	fake := &translate.Fake{}
+	translate.SetDefault(fake)
+	t.Cleanup(func() { translate.SetDefault(nil) })
+
+	get := func(query string) (int, []string, string) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/suggest/translation?"+query, nil))
+		var resp struct {
+			Data struct {
+				Suggestions []string `json:"suggestions"`
+			} `json:"data"`
+			Meta struct {
+				Source string `json:"source"`
+			} `json:"meta"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.Data.Suggestions, resp.Meta.Source
+	}
+	// from/to accept language IDs or codes
+	if code, s, src := get("text=Messer&from=2&to=1"); code != http.StatusOK || len(s) != 1 || s[0] != "hi:Messer" || src != "translator" {
+		t.Fatalf("unexpected first response %d %v %s", code, s, src)
+	}
+	if code, s, src := get("text=%20Messer%20&from=de&to=hi"); code != http.StatusOK || len(s) != 1 || s[0] != "hi:Messer" || src != "cache" {
+		t.Fatalf("expected cached suggestion, got %d %v %s", code, s, src)
+	}
+	// case can change the meaning (Arm/arm), so it is not folded away
+	if code, s, src := get("text=messer&from=de&to=hi"); code != http.StatusOK || len(s) != 1 || s[0] != "hi:messer" || src != "translator" {
+		t.Fatalf("expected lowercase text to miss the cache, got %d %v %s", code, s, src)
+	}
+	if fake.CallCount() != 2 {
+		t.Fatalf("expected two translator calls, got %v", fake.Calls)
+	}
+
+	// another translator does not reuse the first one's suggestions
+	other := &translate.Fake{ID: "other"}
+	translate.SetDefault(other)
+	if code, _, src := get("text=Messer&from=de&to=hi"); code != http.StatusOK || src != "translator" || other.CallCount() != 1 {
+		t.Fatalf("expected a cache miss for another provider, got %d %s %v", code, src, other.Calls)
+	}
+
+	for _, q := range []string{"from=de&to=hi", "text=Messer&from=de&to=xx", "text=Messer&from=de&to=de"} {
+		if code, _, _ := get(q); code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", q, code)
+		}
+	}
+
+	translate.SetDefault(nil)
+	if code, _, _ := get("text=Gabel&from=de&to=hi"); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without a translator, got %d", code)
+	}
+}
+
+func TestSuggestTranslation_DictionaryNotCached(t *testing.T) {
+	h := setup(t)
+	d, err := translate.ParseDictionary(strings.NewReader("de\thi\tMesser\tचाकू\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	translate.SetDefault(translate.Chain{d, &translate.Fake{}})
+	t.Cleanup(func() { translate.SetDefault(nil) })
+
+	get := func(query string) (string, string) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/suggest/translation?"+query, nil))
+		var resp struct {
+			Data struct {
+				Suggestions []string `json:"suggestions"`
+			} `json:"data"`
+			Meta struct {
+				Source string `json:"source"`
+			} `json:"meta"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: got %d %s", query, w.Code, w.Body.String())
+		}
+		return strings.Join(resp.Data.Suggestions, ","), resp.Meta.Source
+	}
+	// the dictionary is asked every time; the fake behind it is cached
+	for _, want := range []string{"translator", "translator"} {
+		if s, src := get("text=Messer&from=de&to=hi"); s != "चाकू" || src != want {
+			t.Fatalf("dictionary: got %q from %s, want %s", s, src, want)
+		}
+	}
+	for _, want := range []string{"translator", "cache"} {
+		if s, src := get("text=Gabel&from=de&to=hi"); s != "hi:Gabel" || src != want {
+			t.Fatalf("fallback: got %q from %s, want %s", s, src, want)
+		}
+	}
+}
+
+func TestSuggestTranslation_FromImportedDictionary(t *testing.T) {
+	h := setup(t)
+	translate.SetDefault(translate.StoredDictionary{})
+	t.Cleanup(func() { translate.SetDefault(nil) })
+	dump := "Messer\tn\tn\tknife\tblade\n" +
+		"Messerstich\tn\tm\tstab\n"
+	if _, err := dictionary.Import(context.Background(), strings.NewReader(dump), dictionary.FormatTSV,
+		dictionary.Options{LangID: "2", SourceLangID: "3", Source: "test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(query string) []string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/suggest/translation?"+query, nil))
+		var resp struct {
+			Data struct {
+				Suggestions []string `json:"suggestions"`
+			} `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: got %d %s", query, w.Code, w.Body.String())
+		}
+		return resp.Data.Suggestions
+	}
+	// exact headword only, not every word it prefixes
+	if got := get("text=MESSER&from=de&to=en"); strings.Join(got, ",") != "knife,blade" {
+		t.Fatalf("expected definitions of Messer, got %v", got)
+	}
+	if got := get("text=Messer&from=de&to=hi"); len(got) != 0 {
+		t.Fatalf("expected nothing for a pair without entries, got %v", got)
+	}
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"learnlang-backend/store"
+	"learnlang-backend/utils"
+
+	"github.com/google/uuid"
+)
+
+// Per-item outcomes reported by the move/copy endpoints.
+const (
+	transferMoved    = "moved"
+	transferCopied   = "copied"
+	transferSkipped  = "skipped"
+	transferConflict = "conflict"
+	transferError    = "error"
+)
+
+// TransferVocabsRequestDTO is the request body for moving or copying vocabs.
+type TransferVocabsRequestDTO struct {
+	UserID       string   `json:"user_id"`
+	VocabIDs     []string `json:"vocab_ids"`
+	TargetPackID string   `json:"target_pack_id"`
+}
+
+// TransferResult reports what happened to a single vocab.
+type TransferResult struct {
+	VocabID string `json:"vocab_id"`
+	Status  string `json:"status"`
+	NewID   string `json:"new_id,omitempty"` // ID of the copy (copy only)
+	Error   string `json:"error,omitempty"`
+	Code    string `json:"code,omitempty"`
+}
+
+// MoveVocabsHandler moves vocabs into another pack owned by the caller.
+func MoveVocabsHandler(w http.ResponseWriter, r *http.Request) {
+	transferVocabs(w, r, false)
+}
+
+// CopyVocabsHandler copies vocabs into another pack owned by the caller.
+// Copies share the original image file. Vocabs may also come from another
+// user's public pack; the target pack is then recorded as a fork of it.
+func CopyVocabsHandler(w http.ResponseWriter, r *http.Request) {
+	transferVocabs(w, r, true)
+}
+
+// transferVocabs validates the target pack once, then processes each vocab
+// independently so one conflict does not block the rest.
+func transferVocabs(w http.ResponseWriter, r *http.Request, asCopy bool) {
+	var req TransferVocabsRequestDTO
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	req.UserID = strings.TrimSpace(req.UserID)
+	req.TargetPackID = strings.TrimSpace(req.TargetPackID)
+	missing := make([]string, 0, 3)
+	if req.UserID == "" {
+		missing = append(missing, "user_id")
+	}
+	if len(req.VocabIDs) == 0 {
+		missing = append(missing, "vocab_ids")
+	}
+	if req.TargetPackID == "" {
+		missing = append(missing, "target_pack_id")
+	}
+	if len(missing) > 0 {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeMissingFields, fmt.Sprintf("missing required field(s): %s", strings.Join(missing, ", ")))
+		return
+	}
+	target, ok := store.GetPackByID(req.TargetPackID)
+	if !ok {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidPack, fmt.Sprintf("unknown pack id: %q", req.TargetPackID))
+		return
+	}
+	if target.UserID != req.UserID {
+		utils.WriteErrorWithRequest(w, r, http.StatusForbidden, utils.CodeInvalidPack, fmt.Sprintf("pack %q does not belong to user %q", target.ID, req.UserID))
+		return
+	}
+
+	// Each vocab is transferred once however often it is listed.
+	ids := make([]string, 0, len(req.VocabIDs))
+	seen := make(map[string]bool, len(req.VocabIDs))
+	for _, id := range req.VocabIDs {
+		if id = strings.TrimSpace(id); !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	results := make([]TransferResult, 0, len(ids))
+	var done int
+	for _, id := range ids {
+		res := TransferResult{VocabID: id}
+		v, ok := store.GetVocabByID(id)
+		if !ok {
+			res.Status, res.Code, res.Error = transferError, utils.CodeInvalidVocab, "unknown vocab id"
+			results = append(results, res)
+			continue
+		}
+		src, ok := store.GetPackByID(v.PackID)
+		switch {
+		case !ok || (src.UserID != req.UserID && !(asCopy && src.Public)):
+			res.Status, res.Code, res.Error = transferError, utils.CodeInvalidVocab, "vocab does not belong to user"
+		case src.LangID != target.LangID:
+			res.Status, res.Code, res.Error = transferError, utils.CodeInvalidLanguage, "target pack is in a different language"
+		case src.SourceLangID != target.SourceLangID:
+			res.Status, res.Code, res.Error = transferError, utils.CodeInvalidLanguage, "target pack has translations in a different language"
+		case !asCopy && src.ID == target.ID:
+			res.Status = transferSkipped
+		case store.VocabExistsByKey(utils.MakeVocabKeyByPackID(target.ID, languageCode(target.LangID), v.Name)):
+			res.Status, res.Code, res.Error = transferConflict, utils.CodeDuplicateVocab, fmt.Sprintf("vocab %q already exists in target pack", v.Name)
+		case asCopy:
+			v.ID = uuid.New().String()
+			v.PackID = target.ID
+			v.Images = store.VocabImages([]string{id})[id]
+			v.Audio = store.VocabAudio([]string{id})[id]
+			if err := store.InsertVocab(v); err != nil {
+				res.Status, res.Code, res.Error = transferError, utils.CodeInternal, "failed to copy vocab"
+				if store.IsUniqueViolation(err) {
+					res.Status, res.Code, res.Error = transferConflict, utils.CodeDuplicateVocab, fmt.Sprintf("vocab %q already exists in target pack", v.Name)
+				}
+				break
+			}
+			if src.UserID != target.UserID {
+				if err := store.MarkForked(target.ID, src.ID); err != nil {
+					log.Printf("mark pack %s forked from %s: %v", target.ID, src.ID, err)
+				}
+			}
+			res.Status, res.NewID = transferCopied, v.ID
+			done++
+		default:
+			if err := store.MoveVocab(v.ID, target.ID); err != nil {
+				res.Status, res.Code, res.Error = transferError, utils.CodeInternal, "failed to move vocab"
+				if store.IsUniqueViolation(err) {
+					res.Status, res.Code, res.Error = transferConflict, utils.CodeDuplicateVocab, fmt.Sprintf("vocab %q already exists in target pack", v.Name)
+				}
+				break
+			}
+			res.Status = transferMoved
+			done++
+		}
+		results = append(results, res)
+	}
+	utils.WriteOKData(w, results, map[string]any{"count": len(results), "succeeded": done, "target_pack_id": target.ID})
+}
@@ -0,0 +1,109 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"learnlang-backend/models"
+	"learnlang-backend/store"
+)
+
+func TestMoveVocabs_ReportsConflicts(t *testing.T) {
+	h := setup(t)
+
+	store.CreatePack(models.Pack{ID: "src", Name: "Old", LangID: "1", UserID: "u1"}, "")
+	store.CreatePack(models.Pack{ID: "dst", Name: "New", LangID: "1", UserID: "u1"}, "")
+	store.CreateVocab(models.Vocab{ID: "v1", Image: "/files/images/a.png", Name: "cat", Translation: "बिल्ली", PackID: "src"}, "")
+	store.CreateVocab(models.Vocab{ID: "v2", Image: "/files/images/b.png", Name: "dog", Translation: "कुत्ता", PackID: "src"}, "")
+	store.CreateVocab(models.Vocab{ID: "v3", Image: "/files/images/c.png", Name: "Dog", Translation: "कुत्ता", PackID: "dst"}, "")
+
+	body, _ := json.Marshal(map[string]any{"user_id": "u1", "vocab_ids": []string{"v1", "v2"}, "target_pack_id": "dst"})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/vocabs/move", bytes.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data []map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(resp.Data) != 2 || resp.Data[0]["status"] != "moved" || resp.Data[1]["status"] != "conflict" {
+		t.Fatalf("unexpected results: %#v", resp.Data)
+	}
+	if v, _ := store.GetVocabByID("v1"); v.PackID != "dst" {
+		t.Fatalf("expected v1 in dst, got %q", v.PackID)
+	}
+}
+
+func TestCopyVocabs_RejectsForeignTarget(t *testing.T) {
+	h := setup(t)
+
+	store.CreatePack(models.Pack{ID: "mine", Name: "Mine", LangID: "1", UserID: "u1"}, "")
+	store.CreatePack(models.Pack{ID: "theirs", Name: "Theirs", LangID: "1", UserID: "u2"}, "")
+	store.CreateVocab(models.Vocab{ID: "v1", Image: "/files/images/a.png", Name: "cat", Translation: "बिल्ली", PackID: "mine"}, "")
+
+	body, _ := json.Marshal(map[string]any{"user_id": "u1", "vocab_ids": []string{"v1"}, "target_pack_id": "theirs"})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/vocabs/copy", bytes.NewReader(body)))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestCopyVocabs_FromPublicPackCountsAsFork(t *testing.T) {
+	h := setup(t)
+
+	store.CreatePack(models.Pack{ID: "pub", Name: "Farm", LangID: "1", UserID: "u1", Public: true}, "")
+	store.CreatePack(models.Pack{ID: "priv", Name: "Secret", LangID: "1", UserID: "u1"}, "")
+	store.CreatePack(models.Pack{ID: "mine", Name: "My Farm", LangID: "1", UserID: "u2"}, "")
+	store.CreateVocab(models.Vocab{ID: "v1", Image: "/files/images/a.png", Name: "cow", Translation: "गाय", PackID: "pub"}, "")
+	store.CreateVocab(models.Vocab{ID: "v2", Image: "/files/images/b.png", Name: "cat", Translation: "बिल्ली", PackID: "priv"}, "")
+
+	body, _ := json.Marshal(map[string]any{"user_id": "u2", "vocab_ids": []string{"v1", "v2"}, "target_pack_id": "mine"})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/vocabs/copy", bytes.NewReader(body)))
+	var resp struct {
+		Data []map[string]any `json:"data"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if w.Code != http.StatusOK || len(resp.Data) != 2 || resp.Data[0]["status"] != "copied" || resp.Data[1]["status"] != "error" {
+		t.Fatalf("expected public vocab copied and private one rejected, got %d %#v", w.Code, resp.Data)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/public/packs", nil))
+	var cat struct {
+		Data []models.PublicPack `json:"data"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &cat)
+	if len(cat.Data) != 1 || cat.Data[0].Forks != 1 || cat.Data[0].Learners != 1 {
+		t.Fatalf("expected one fork and learner, got %+v", cat.Data)
+	}
+}
+
+func TestCopyVocabs_DedupesIDs(t *testing.T) {
+	h := setup(t)
+
+	store.CreatePack(models.Pack{ID: "src", Name: "Old", LangID: "1", UserID: "u1"}, "")
+	store.CreatePack(models.Pack{ID: "dst", Name: "New", LangID: "1", UserID: "u1"}, "")
+	store.CreateVocab(models.Vocab{ID: "v1", Image: "/files/images/a.png", Name: "cat", Translation: "बिल्ली", PackID: "src"}, "")
+
+	body, _ := json.Marshal(map[string]any{"user_id": "u1", "vocab_ids": []string{"v1", " v1", "v1"}, "target_pack_id": "dst"})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/vocabs/copy", bytes.NewReader(body)))
+	var resp struct {
+		Data []map[string]any `json:"data"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if w.Code != http.StatusOK || len(resp.Data) != 1 || resp.Data[0]["status"] != "copied" {
+		t.Fatalf("expected a single copy, got %d %#v", w.Code, resp.Data)
+	}
+	if n := len(store.ListVocabsByPackID("dst", store.VocabSort{})); n != 1 {
+		t.Fatalf("expected 1 vocab in dst, got %d", n)
+	}
+}
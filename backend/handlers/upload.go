@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"learnlang-backend/blob"
+	"learnlang-backend/models"
+	"learnlang-backend/store"
+	"learnlang-backend/utils"
+)
+
+// maxImageSize is the per-file limit for uploaded images.
+const maxImageSize = 10 << 20 // 10MB
+
+// maxAudioSize is the per-file limit for uploaded pronunciation clips.
+const maxAudioSize = 5 << 20 // 5MB
+
+// maxAudioClips caps how many clips a single request may attach.
+const maxAudioClips = 5
+
+// uploadError describes why an uploaded file was rejected.
+type uploadError struct {
+	Status int
+	Code   string
+	Msg    string
+}
+
+// write sends the upload error as a standard JSON error response.
+func (e *uploadError) write(w http.ResponseWriter, r *http.Request) {
+	utils.WriteErrorWithRequest(w, r, e.Status, e.Code, e.Msg)
+}
+
+// saveImageUpload enforces size and image/* content type (via sniffing) on an
+// uploaded file, saves it with utils.UploadImage and records the media blob.
+func saveImageUpload(w http.ResponseWriter, file multipart.File, header *multipart.FileHeader) (utils.StoredFile, *uploadError) {
+	// Quick size check from header if available
+	if header.Size > 0 && header.Size > maxImageSize {
+		return utils.StoredFile{}, &uploadError{http.StatusRequestEntityTooLarge, utils.CodeFileTooLarge, "file too large; max 10MB"}
+	}
+	limited := http.MaxBytesReader(w, file, maxImageSize)
+	// Read first 512 bytes to detect content type
+	head := make([]byte, 512)
+	n, err := io.ReadFull(limited, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) || errors.Is(err, io.EOF) {
+			return utils.StoredFile{}, &uploadError{http.StatusRequestEntityTooLarge, utils.CodeFileTooLarge, "file too large; max 10MB"}
+		}
+		return utils.StoredFile{}, &uploadError{http.StatusBadRequest, utils.CodeInvalidFileType, "could not read file header"}
+	}
+	contentType := http.DetectContentType(head[:n])
+	if !strings.HasPrefix(contentType, "image/") {
+		return utils.StoredFile{}, &uploadError{http.StatusBadRequest, utils.CodeInvalidFileType, fmt.Sprintf("unsupported file type: %s", contentType)}
+	}
+
+	img, err := utils.UploadImage(contentType, head, n, limited)
+	if err != nil {
+		// Map a few known errors to 4xx
+		if strings.Contains(err.Error(), "unknown file type") {
+			return utils.StoredFile{}, &uploadError{http.StatusBadRequest, utils.CodeInvalidFileType, "file type could not be determined"}
+		}
+		if errors.Is(err, utils.ErrInvalidImage) {
+			return utils.StoredFile{}, &uploadError{http.StatusBadRequest, utils.CodeInvalidFileType, "file is not a valid image"}
+		}
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			return utils.StoredFile{}, &uploadError{http.StatusRequestEntityTooLarge, utils.CodeFileTooLarge, "file too large; max 10MB"}
+		}
+		return utils.StoredFile{}, &uploadError{http.StatusInternalServerError, utils.CodeInternal, "failed to save file"}
+	}
+	recordMedia(img)
+	return img, nil
+}
+
+// saveAudioUploads stores every "audio" file part of a parsed multipart form.
+// Each clip is size-limited and sniffed like images. Clips saved before an
+// error are left for the garbage collector, like any other unreferenced blob.
+func saveAudioUploads(w http.ResponseWriter, r *http.Request) ([]utils.StoredFile, *uploadError) {
+	if r.MultipartForm == nil {
+		return nil, nil
+	}
+	headers := r.MultipartForm.File["audio"]
+	if len(headers) > maxAudioClips {
+		return nil, &uploadError{http.StatusBadRequest, utils.CodeInvalidParam, fmt.Sprintf("too many audio clips; max %d", maxAudioClips)}
+	}
+	saved := make([]utils.StoredFile, 0, len(headers))
+	for _, h := range headers {
+		f, uerr := saveAudioUpload(w, h)
+		if uerr != nil {
+			return nil, uerr
+		}
+		saved = append(saved, f)
+	}
+	return saved, nil
+}
+
+func saveAudioUpload(w http.ResponseWriter, header *multipart.FileHeader) (utils.StoredFile, *uploadError) {
+	if header.Size > maxAudioSize {
+		return utils.StoredFile{}, &uploadError{http.StatusRequestEntityTooLarge, utils.CodeFileTooLarge, "audio too large; max 5MB"}
+	}
+	file, err := header.Open()
+	if err != nil {
+		return utils.StoredFile{}, &uploadError{http.StatusBadRequest, utils.CodeInvalidJSON, "invalid uploaded file"}
+	}
+	defer file.Close()
+	limited := http.MaxBytesReader(w, file, maxAudioSize)
+	head := make([]byte, 512)
+	n, err := io.ReadFull(limited, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			return utils.StoredFile{}, &uploadError{http.StatusRequestEntityTooLarge, utils.CodeFileTooLarge, "audio too large; max 5MB"}
+		}
+		return utils.StoredFile{}, &uploadError{http.StatusBadRequest, utils.CodeInvalidFileType, "could not read file header"}
+	}
+	contentType, ok := utils.SniffAudio(head[:n])
+	if !ok {
+		return utils.StoredFile{}, &uploadError{http.StatusBadRequest, utils.CodeInvalidFileType, fmt.Sprintf("unsupported audio type: %s", http.DetectContentType(head[:n]))}
+	}
+	f, err := utils.UploadAudio(contentType, head, n, limited)
+	if err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			return utils.StoredFile{}, &uploadError{http.StatusRequestEntityTooLarge, utils.CodeFileTooLarge, "audio too large; max 5MB"}
+		}
+		return utils.StoredFile{}, &uploadError{http.StatusInternalServerError, utils.CodeInternal, "failed to save file"}
+	}
+	recordMedia(f)
+	return f, nil
+}
+
+// resolveAudioRefs checks JSON audio references, which must be existing
+// /files/audio/ uploads.
+func resolveAudioRefs(r *http.Request, refs []string) ([]string, *uploadError) {
+	if len(refs) > maxAudioClips {
+		return nil, &uploadError{http.StatusBadRequest, utils.CodeInvalidParam, fmt.Sprintf("too many audio clips; max %d", maxAudioClips)}
+	}
+	out := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		ref = strings.TrimSpace(ref)
+		key, ok := utils.UploadKey(ref)
+		if !ok || !strings.HasPrefix(ref, "/files/audio/") {
+			return nil, &uploadError{http.StatusBadRequest, utils.CodeInvalidParam, fmt.Sprintf("invalid audio path: %q", ref)}
+		}
+		if _, err := blob.Default().Stat(r.Context(), key); err != nil {
+			return nil, &uploadError{http.StatusBadRequest, utils.CodeInvalidParam, fmt.Sprintf("audio not found: %q", ref)}
+		}
+		out = append(out, ref)
+	}
+	return out, nil
+}
+
+// storedURLs returns the public URLs of saved files.
+func storedURLs(fs []utils.StoredFile) []string {
+	out := make([]string, len(fs))
+	for i, f := range fs {
+		out[i] = f.URL
+	}
+	return out
+}
+
+// remoteImageClient fetches images referenced by URL; it refuses private addresses.
+var remoteImageClient = utils.NewPublicHTTPClient(10 * time.Second)
+
+// resolveImageRef turns a JSON image reference into a public /files/ URL.
+// Existing uploads are reused as-is; http(s) URLs are fetched and saved.
+func resolveImageRef(r *http.Request, ref string) (string, *uploadError) {
+	if strings.HasPrefix(ref, "/files/") {
+		key, ok := utils.UploadKey(ref)
+		if !ok || !strings.HasPrefix(ref, "/files/images/") {
+			return "", &uploadError{http.StatusBadRequest, utils.CodeInvalidImageURL, fmt.Sprintf("invalid image path: %q", ref)}
+		}
+		if _, err := blob.Default().Stat(r.Context(), key); err != nil {
+			return "", &uploadError{http.StatusBadRequest, utils.CodeInvalidImageURL, fmt.Sprintf("image not found: %q", ref)}
+		}
+		return ref, nil
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+	img, err := utils.FetchRemoteImage(ctx, remoteImageClient, ref, maxImageSize)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrBlockedAddress):
+			return "", &uploadError{http.StatusBadRequest, utils.CodeInvalidImageURL, fmt.Sprintf("image url not allowed: %q", ref)}
+		case errors.Is(err, utils.ErrRemoteTooLarge):
+			return "", &uploadError{http.StatusRequestEntityTooLarge, utils.CodeFileTooLarge, "file too large; max 10MB"}
+		case errors.Is(err, utils.ErrRemoteNotImage):
+			return "", &uploadError{http.StatusBadRequest, utils.CodeInvalidFileType, "remote file is not an image"}
+		default:
+			return "", &uploadError{http.StatusBadGateway, utils.CodeImageFetchFailed, "failed to fetch image"}
+		}
+	}
+	defer img.Close()
+
+	stored, err := utils.UploadImage(img.ContentType, img.Head, len(img.Head), img.Rest)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "unknown file type"):
+			return "", &uploadError{http.StatusBadRequest, utils.CodeInvalidFileType, "file type could not be determined"}
+		case errors.Is(err, utils.ErrInvalidImage):
+			return "", &uploadError{http.StatusBadRequest, utils.CodeInvalidFileType, "file is not a valid image"}
+		case errors.Is(err, utils.ErrRemoteTooLarge):
+			return "", &uploadError{http.StatusRequestEntityTooLarge, utils.CodeFileTooLarge, "file too large; max 10MB"}
+		default:
+			return "", &uploadError{http.StatusBadGateway, utils.CodeImageFetchFailed, "failed to fetch image"}
+		}
+	}
+	recordMedia(stored)
+	return stored.URL, nil
+}
+
+// recordMedia registers a stored blob. The file is already safely on disk, so
+// a failure is only logged.
+func recordMedia(img utils.StoredFile) {
+	err := store.RecordMedia(models.Media{Hash: img.Hash, Path: img.URL, ContentType: img.ContentType, Size: img.Size})
+	if err != nil {
+		log.Printf("record media %s: %v", img.URL, err)
+	}
+}
@@ -1,12 +1,11 @@
 package handlers
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"math/rand"
 	"net/http"
-	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -19,29 +18,46 @@ import (
 	"github.com/google/uuid"
 )
 
-// CreateVocabRequestDTO represents request body to create a vocab.
+// CreateVocabRequestDTO represents a JSON request body to create a vocab.
 // For multipart form uploads, we read from form fields instead of JSON body.
-// This DTO remains for compatibility if you later accept JSON+URL uploads.
 type CreateVocabRequestDTO struct {
-	Image       string `json:"image"` // optional if using multipart file upload
-	Name        string `json:"name"`
-	Translation string `json:"translation"`
-	PackID      string `json:"pack_id"`
+	Image       string   `json:"image"` // existing /files/images/... path or http(s) URL to fetch
+	Name        string   `json:"name"`
+	Translation string   `json:"translation"`
+	PackID      string   `json:"pack_id"`
+	Audio       []string `json:"audio"` // existing /files/audio/... paths
+
+	Examples     []models.Example `json:"examples"`
+	Notes        string           `json:"notes"`
+	PartOfSpeech string           `json:"part_of_speech"`
+	Tags         []string         `json:"tags"`
+	Grammar      *models.Grammar  `json:"grammar"`
+
+	Transliteration string `json:"transliteration"` // generated for Hindi when empty
+
+	Translations []string `json:"translations"` // extra accepted translations; the first is primary if translation is empty
+	Synonyms     []string `json:"synonyms"`
+
+	Difficulty int `json:"difficulty"` // 1 (easy) to 5 (hard); 0 unrated
 }
 
 // CreateVocabHandler creates a new vocab entry under a pack.
+// Accepts multipart/form-data with an image file and optional "audio" files,
+// or JSON with an image reference.
 func CreateVocabHandler(w http.ResponseWriter, r *http.Request) {
 	// Support multipart form for file uploads
 	var (
 		name        string
 		translation string
 		packID      string
-		imgURL      string
 	)
 	ct := r.Header.Get("Content-Type")
+	if strings.HasPrefix(ct, "application/json") {
+		createVocabFromJSON(w, r)
+		return
+	}
 	if !strings.HasPrefix(ct, "multipart/form-data") {
-		// Only multipart is supported now
-		utils.WriteErrorWithRequest(w, r, http.StatusUnsupportedMediaType, utils.CodeInvalidJSON, "multipart/form-data required")
+		utils.WriteErrorWithRequest(w, r, http.StatusUnsupportedMediaType, utils.CodeInvalidJSON, "multipart/form-data or application/json required")
 		return
 	}
 	if err := r.ParseMultipartForm(10 << 20); err != nil { // 10MB
@@ -53,66 +69,24 @@ func CreateVocabHandler(w http.ResponseWriter, r *http.Request) {
 	packID = strings.TrimSpace(r.FormValue("pack_id"))
 
 	file, header, err := r.FormFile("image")
-	if err != nil {
-		if errors.Is(err, http.ErrMissingFile) {
-			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeMissingFields, "missing required field(s): image")
-			return
-		}
+	if err != nil && !errors.Is(err, http.ErrMissingFile) {
 		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidJSON, "invalid uploaded file")
 		return
 	}
-	defer file.Close()
-
-	// Enforce max size (10MB) and type (image/*) via sniffing
-	const maxSize = 10 << 20 // 10MB
-	// Quick size check from header if available
-	if header.Size > 0 && header.Size > maxSize {
-		utils.WriteErrorWithRequest(w, r, http.StatusRequestEntityTooLarge, utils.CodeFileTooLarge, "file too large; max 10MB")
-		return
-	}
-	limited := http.MaxBytesReader(w, file, maxSize)
-	// Read first 512 bytes to detect content type
-	head := make([]byte, 512)
-	n, err := io.ReadFull(limited, head)
-	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
-		var mbe *http.MaxBytesError
-		if errors.As(err, &mbe) || errors.Is(err, io.EOF) {
-			utils.WriteErrorWithRequest(w, r, http.StatusRequestEntityTooLarge, utils.CodeFileTooLarge, "file too large; max 10MB")
-			return
-		}
-		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidFileType, "could not read file header")
-		return
-	}
-	contentType := http.DetectContentType(head[:n])
-	if !strings.HasPrefix(contentType, "image/") {
-		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidFileType, fmt.Sprintf("unsupported file type: %s", contentType))
-		return
+	if file != nil {
+		defer file.Close()
 	}
 
-	// Save file using utils.UploadImage
-	url, err := utils.UploadImage(name, header.Filename, contentType, head, n, limited)
-	if err != nil {
-		// Map a few known errors to 4xx
-		if strings.Contains(err.Error(), "unknown file type") {
-			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidFileType, "file type could not be determined")
-			return
-		}
-		var mbe *http.MaxBytesError
-		if errors.As(err, &mbe) {
-			utils.WriteErrorWithRequest(w, r, http.StatusRequestEntityTooLarge, utils.CodeFileTooLarge, "file too large; max 10MB")
-			return
-		}
-		utils.WriteErrorWithRequest(w, r, http.StatusInternalServerError, utils.CodeInternal, "failed to save file")
-		return
-	}
-	imgURL = url
+	details := models.Vocab{Name: name, Translation: translation}
+	detailsErr := formVocabDetails(r, &details)
+	translation = details.Translation
 
-	// Validate
+	// Validate everything before any file is stored.
 	missing := make([]string, 0, 3)
 	if packID == "" {
 		missing = append(missing, "pack_id")
 	}
-	if imgURL == "" {
+	if file == nil {
 		missing = append(missing, "image")
 	}
 	if name == "" {
@@ -125,27 +99,137 @@ func CreateVocabHandler(w http.ResponseWriter, r *http.Request) {
 		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeMissingFields, fmt.Sprintf("missing required field(s): %s", strings.Join(missing, ", ")))
 		return
 	}
+	if detailsErr != nil {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, detailsErr.Error())
+		return
+	}
 	// pack must exist
-	if _, ok := store.GetPackByID(packID); !ok {
+	pack, ok := store.GetPackByID(packID)
+	if !ok {
 		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidPack, fmt.Sprintf("unknown pack id: %q", packID))
 		return
 	}
+	langCode := languageCode(pack.LangID)
+	if err := utils.NormalizeGrammar(langCode, &details); err != nil {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, err.Error())
+		return
+	}
+	if details.Transliteration == "" {
+		details.Transliteration = utils.AutoTransliteration(langCode, details)
+	}
 	// uniqueness per pack/name
-	vocabKey := utils.MakeVocabKeyByPackID(packID, name)
+	vocabKey := utils.MakeVocabKeyByPackID(packID, langCode, name)
 	if store.VocabExistsByKey(vocabKey) {
 		utils.WriteErrorWithRequest(w, r, http.StatusConflict, utils.CodeDuplicateVocab, fmt.Sprintf("vocab %q already exists in this pack", name))
 		return
 	}
 
-	v := models.Vocab{
-		ID:          uuid.New().String(),
-		Image:       imgURL,
-		Name:        name,
-		Translation: translation,
-		PackID:      packID,
-	}
-	store.CreateVocab(v, vocabKey)
-	utils.WriteCreatedData(w, v, nil)
+	img, uerr := saveImageUpload(w, file, header)
+	if uerr != nil {
+		uerr.write(w, r)
+		return
+	}
+	clips, uerr := saveAudioUploads(w, r)
+	if uerr != nil {
+		uerr.write(w, r)
+		return
+	}
+
+	v := details
+	v.ID = uuid.New().String()
+	v.Image = img.URL
+	v.PackID = packID
+	v.Audio = storedURLs(clips)
+	insertVocab(w, r, v)
+}
+
+// insertVocab stores v with its audio and writes the created response. A
+// unique violation means a concurrent request created the same name first.
+func insertVocab(w http.ResponseWriter, r *http.Request, v models.Vocab) {
+	if err := store.InsertVocab(v); err != nil {
+		if store.IsUniqueViolation(err) {
+			utils.WriteErrorWithRequest(w, r, http.StatusConflict, utils.CodeDuplicateVocab, fmt.Sprintf("vocab %q already exists in this pack", v.Name))
+			return
+		}
+		utils.WriteErrorWithRequest(w, r, http.StatusInternalServerError, utils.CodeInternal, "failed to create vocab")
+		return
+	}
+	utils.WriteCreatedData(w, withVocabMedia(v), nil)
+}
+
+// createVocabFromJSON creates a vocab whose image is either an existing
+// uploaded file or a remote http(s) URL that is fetched and stored locally.
+// As in the multipart flow, everything is validated before any file is written.
+func createVocabFromJSON(w http.ResponseWriter, r *http.Request) {
+	var req CreateVocabRequestDTO
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	req.Translation = strings.TrimSpace(req.Translation)
+	req.PackID = strings.TrimSpace(req.PackID)
+	req.Image = strings.TrimSpace(req.Image)
+	if req.Translation == "" && len(req.Translations) > 0 {
+		req.Translation = strings.TrimSpace(req.Translations[0])
+	}
+	missing := make([]string, 0, 4)
+	if req.PackID == "" {
+		missing = append(missing, "pack_id")
+	}
+	if req.Image == "" {
+		missing = append(missing, "image")
+	}
+	if req.Name == "" {
+		missing = append(missing, "name")
+	}
+	if req.Translation == "" {
+		missing = append(missing, "translation")
+	}
+	if len(missing) > 0 {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeMissingFields, fmt.Sprintf("missing required field(s): %s", strings.Join(missing, ", ")))
+		return
+	}
+	details := models.Vocab{Name: req.Name, Translation: req.Translation, Examples: req.Examples, Notes: req.Notes, PartOfSpeech: req.PartOfSpeech, Tags: req.Tags, Grammar: req.Grammar, Transliteration: req.Transliteration,
+		Translations: req.Translations, Synonyms: req.Synonyms, Difficulty: req.Difficulty}
+	if err := utils.NormalizeVocabDetails(&details); err != nil {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, err.Error())
+		return
+	}
+	pack, ok := store.GetPackByID(req.PackID)
+	if !ok {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidPack, fmt.Sprintf("unknown pack id: %q", req.PackID))
+		return
+	}
+	langCode := languageCode(pack.LangID)
+	if err := utils.NormalizeGrammar(langCode, &details); err != nil {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, err.Error())
+		return
+	}
+	if details.Transliteration == "" {
+		details.Transliteration = utils.AutoTransliteration(langCode, details)
+	}
+	vocabKey := utils.MakeVocabKeyByPackID(req.PackID, langCode, req.Name)
+	if store.VocabExistsByKey(vocabKey) {
+		utils.WriteErrorWithRequest(w, r, http.StatusConflict, utils.CodeDuplicateVocab, fmt.Sprintf("vocab %q already exists in this pack", req.Name))
+		return
+	}
+
+	audio, uerr := resolveAudioRefs(r, req.Audio)
+	if uerr != nil {
+		uerr.write(w, r)
+		return
+	}
+	imgURL, uerr := resolveImageRef(r, req.Image)
+	if uerr != nil {
+		uerr.write(w, r)
+		return
+	}
+	v := details
+	v.ID = uuid.New().String()
+	v.Image = imgURL
+	v.PackID = req.PackID
+	v.Audio = audio
+	insertVocab(w, r, v)
 }
 
 // UpdateVocabHandler updates name/translation and optionally replaces the image.
@@ -153,6 +237,9 @@ func CreateVocabHandler(w http.ResponseWriter, r *http.Request) {
 // - name (optional)
 // - translation (optional)
 // - image (optional file)
+// - audio (optional files, appended to existing clips)
+// - remove_audio (optional, repeatable clip URL to detach)
+// - translations, synonyms, notes, part_of_speech, tags, examples, grammar, transliteration, difficulty (optional; see formVocabDetails)
 // If no image is provided, existing image stays.
 func UpdateVocabHandler(w http.ResponseWriter, r *http.Request) {
 	// Extract ID from URL
@@ -178,78 +265,148 @@ func UpdateVocabHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	name := strings.TrimSpace(r.FormValue("name"))
 	translation := strings.TrimSpace(r.FormValue("translation"))
+	source := utils.TransliterationSource(v)
 
 	// If provided, apply textual changes
 	if name != "" {
 		v.Name = name
 	}
 	if translation != "" {
+		// A new primary replaces the old one among the accepted translations.
+		if len(v.Translations) > 0 {
+			v.Translations[0] = translation
+		}
 		v.Translation = translation
 	}
+	if err := formVocabDetails(r, &v); err != nil {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, err.Error())
+		return
+	}
+	// Re-check grammar even if unchanged: it depends on name and part of speech.
+	pack, _ := store.GetPackByID(v.PackID)
+	langCode := languageCode(pack.LangID)
+	if err := utils.NormalizeGrammar(langCode, &v); err != nil {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, err.Error())
+		return
+	}
+	// Regenerate the transliteration when cleared, or when its source text
+	// changed and the user did not send a new one.
+	if _, edited := r.MultipartForm.Value["transliteration"]; v.Transliteration == "" || !edited && utils.TransliterationSource(v) != source {
+		v.Transliteration = utils.AutoTransliteration(langCode, v)
+	}
 
 	// Optional image replacement
 	file, header, err := r.FormFile("image")
 	if err == nil {
 		defer file.Close()
-		// Enforce max size and image content type
-		const maxSize = 10 << 20
-		if header.Size > 0 && header.Size > maxSize {
-			utils.WriteErrorWithRequest(w, r, http.StatusRequestEntityTooLarge, utils.CodeFileTooLarge, "file too large; max 10MB")
+		img, uerr := saveImageUpload(w, file, header)
+		if uerr != nil {
+			uerr.write(w, r)
 			return
 		}
-		limited := http.MaxBytesReader(w, file, maxSize)
-		head := make([]byte, 512)
-		n, err := io.ReadFull(limited, head)
-		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
-			var mbe *http.MaxBytesError
-			if errors.As(err, &mbe) || errors.Is(err, io.EOF) {
-				utils.WriteErrorWithRequest(w, r, http.StatusRequestEntityTooLarge, utils.CodeFileTooLarge, "file too large; max 10MB")
-				return
-			}
-			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidFileType, "could not read file header")
+		v.Image = img.URL
+	}
+	clips, uerr := saveAudioUploads(w, r)
+	if uerr != nil {
+		uerr.write(w, r)
+		return
+	}
+
+	// Persist
+	if err := store.UpdateVocabWithAudio(v, r.MultipartForm.Value["remove_audio"], storedURLs(clips)); err != nil {
+		if store.IsUniqueViolation(err) {
+			utils.WriteErrorWithRequest(w, r, http.StatusConflict, utils.CodeDuplicateVocab, fmt.Sprintf("vocab %q already exists in this pack", v.Name))
 			return
 		}
-		contentType := http.DetectContentType(head[:n])
-		if !strings.HasPrefix(contentType, "image/") {
-			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidFileType, fmt.Sprintf("unsupported file type: %s", contentType))
-			return
+		utils.WriteErrorWithRequest(w, r, http.StatusInternalServerError, utils.CodeInternal, "failed to update vocab")
+		return
+	}
+	utils.WriteOKData(w, withVocabMedia(v), nil)
+}
+
+// formVocabDetails reads the optional detail fields of a parsed multipart form
+// into v and validates them. Only fields present in the form change, so an
+// update can clear one by sending it empty (an empty transliteration is
+// regenerated). examples is a JSON array of {"text", "translation"}; tags,
+// translations and synonyms may be comma-separated or repeated; grammar is a
+// JSON object checked separately by utils.NormalizeGrammar.
+func formVocabDetails(r *http.Request, v *models.Vocab) error {
+	form := r.MultipartForm.Value
+	if vals, ok := form["notes"]; ok {
+		v.Notes = strings.Join(vals, "\n")
+	}
+	if _, ok := form["transliteration"]; ok {
+		v.Transliteration = r.FormValue("transliteration")
+	}
+	if _, ok := form["part_of_speech"]; ok {
+		v.PartOfSpeech = r.FormValue("part_of_speech")
+	}
+	if _, ok := form["difficulty"]; ok {
+		v.Difficulty = 0
+		if raw := strings.TrimSpace(r.FormValue("difficulty")); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				return fmt.Errorf("invalid difficulty %q: expected a number", raw)
+			}
+			v.Difficulty = n
 		}
-		// Use existing name when saving replacement file to keep naming consistent
-		baseName := v.Name
-		if baseName == "" {
-			baseName = strings.TrimSuffix(filepath.Base(header.Filename), filepath.Ext(header.Filename))
+	}
+	if vals, ok := form["tags"]; ok {
+		v.Tags = formList(vals)
+	}
+	if vals, ok := form["translations"]; ok {
+		v.Translations = formList(vals)
+	}
+	if vals, ok := form["synonyms"]; ok {
+		v.Synonyms = formList(vals)
+	}
+	if _, ok := form["examples"]; ok {
+		v.Examples = nil
+		if raw := strings.TrimSpace(r.FormValue("examples")); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &v.Examples); err != nil {
+				return fmt.Errorf("invalid examples: expected a JSON array of {\"text\", \"translation\"}")
+			}
 		}
-		url, err := utils.UploadImage(baseName, header.Filename, contentType, head, n, limited)
-		if err != nil {
-			var mbe *http.MaxBytesError
-			if errors.As(err, &mbe) {
-				utils.WriteErrorWithRequest(w, r, http.StatusRequestEntityTooLarge, utils.CodeFileTooLarge, "file too large; max 10MB")
-				return
+	}
+	if _, ok := form["grammar"]; ok {
+		v.Grammar = nil
+		if raw := strings.TrimSpace(r.FormValue("grammar")); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &v.Grammar); err != nil {
+				return fmt.Errorf("invalid grammar: expected a JSON object")
 			}
-			utils.WriteErrorWithRequest(w, r, http.StatusInternalServerError, utils.CodeInternal, "failed to save file")
-			return
 		}
-		v.Image = url
 	}
+	return utils.NormalizeVocabDetails(v)
+}
 
-	// Persist
-	if err := store.UpdateVocab(v); err != nil {
-		utils.WriteErrorWithRequest(w, r, http.StatusInternalServerError, utils.CodeInternal, "failed to update vocab")
-		return
+// formList splits repeated, comma-separated form values into one list.
+func formList(vals []string) []string {
+	var out []string
+	for _, val := range vals {
+		out = append(out, strings.Split(val, ",")...)
 	}
-	utils.WriteOKData(w, v, nil)
+	return out
 }
 
 // Flashcard represents a simplified view for the game (hide translation by default on UI).
 type Flashcard struct {
-	ID       string `json:"id"`
-	Image    string `json:"image"`
-	Name     string `json:"name"`
-	PackName string `json:"pack_name"`
+	ID              string            `json:"id"`
+	Image           string            `json:"image"`
+	ImageVariants   map[string]string `json:"image_variants,omitempty"`
+	Audio           string            `json:"audio,omitempty"` // first recorded clip, else cached TTS audio
+	Name            string            `json:"name,omitempty"`  // omitted in name mode: it is the answer
+	PackName        string            `json:"pack_name"`
+	Transliteration string            `json:"transliteration,omitempty"` // romanised Devanagari (Hindi)
+	Translation     string            `json:"translation,omitempty"`     // name mode only: the prompt
+	Mode            string            `json:"mode,omitempty"`            // quiz mode, see CheckAnswerHandler
+	Choices         []string          `json:"choices,omitempty"`         // article mode: der, die, das
 }
 
 // GetFlashcardsHandler returns randomized flashcards for a user and language
-// Optional query: packs=pack1,pack2 and limit=n (defaults to all and 20 max)
+// Optional query: packs=pack1,pack2 and limit=n (defaults to all and 20 max);
+// source_lang_id keeps only packs translated into that language.
+// mode=name shows the translation and asks for the name; mode=article quizzes
+// the definite article of nouns with a known gender.
 func GetFlashcardsHandler(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
 	userID := strings.TrimSpace(q.Get("user_id"))
@@ -266,15 +423,28 @@ func GetFlashcardsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// validate language ID
-	var ok2 bool
-	for _, l := range store.LanguagesList() {
-		if l.ID == lang {
-			ok2 = true
-			break
+	langCode := languageCode(lang)
+	if langCode == "" {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidLanguage, fmt.Sprintf("unsupported language id: %q", lang))
+		return
+	}
+	mode := strings.TrimSpace(q.Get("mode"))
+	switch mode {
+	case "", quizModeTranslation:
+		mode = ""
+	case quizModeName:
+	case quizModeArticle:
+		if utils.Articles(langCode) == nil {
+			utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, fmt.Sprintf("article quiz is not available for language %q", langCode))
+			return
 		}
+	default:
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidParam, fmt.Sprintf("unsupported mode: %q", mode))
+		return
 	}
-	if !ok2 {
-		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidLanguage, fmt.Sprintf("unsupported language id: %q", lang))
+	source := strings.TrimSpace(q.Get("source_lang_id"))
+	if source != "" && !languageIDExists(source) {
+		utils.WriteErrorWithRequest(w, r, http.StatusBadRequest, utils.CodeInvalidLanguage, fmt.Sprintf("unsupported language id: %q", source))
 		return
 	}
 	packsCSV := strings.TrimSpace(q.Get("pack_ids"))
@@ -305,7 +475,16 @@ func GetFlashcardsHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	vocabs := store.ListVocabs(userID, lang, packs)
+	vocabs := store.ListVocabs(userID, lang, source, packs)
+	if mode == quizModeArticle {
+		nouns := vocabs[:0]
+		for _, v := range vocabs {
+			if _, ok := utils.DefiniteArticle(langCode, v.Grammar); ok {
+				nouns = append(nouns, v)
+			}
+		}
+		vocabs = nouns
+	}
 	if len(vocabs) == 0 {
 		utils.WriteOKData(w, []Flashcard{}, nil)
 		return
@@ -316,12 +495,41 @@ func GetFlashcardsHandler(w http.ResponseWriter, r *http.Request) {
 	if len(vocabs) > limit {
 		vocabs = vocabs[:limit]
 	}
+	vocabs = withAudio(withImages(vocabs))
+	ids := make([]string, len(vocabs))
+	for i, v := range vocabs {
+		ids[i] = v.ID
+	}
+	synthesized := store.VocabTTSAudio(ids)
 	cards := make([]Flashcard, len(vocabs))
 	for i, v := range vocabs {
 		pack, _ := store.GetPackByID(v.PackID)
-		cards[i] = Flashcard{ID: v.ID, Image: v.Image, Name: v.Name, PackName: pack.Name}
+		// Show a random image each time so learners remember the word, not one picture.
+		img := v.Image
+		if len(v.Images) > 0 {
+			img = v.Images[rsrc.Intn(len(v.Images))]
+		}
+		cards[i] = Flashcard{ID: v.ID, Image: img, ImageVariants: utils.ImageVariants(img), PackName: pack.Name, Mode: mode}
+		// In name mode the name is the answer, so neither it nor anything
+		// that spells or pronounces it goes on the card.
+		if mode == quizModeName {
+			cards[i].Translation = v.Translation
+			continue
+		}
+		cards[i].Name = v.Name
+		cards[i].Transliteration = v.Transliteration
+		if cards[i].Transliteration == "" {
+			cards[i].Transliteration = utils.AutoTransliteration(langCode, v)
+		}
+		if mode == quizModeArticle {
+			cards[i].Choices = utils.Articles(langCode)
+		}
+		// Recorded clips win over cached synthesized pronunciations.
+		if len(v.Audio) > 0 {
+			cards[i].Audio = v.Audio[0]
+		} else {
+			cards[i].Audio = synthesized[v.ID]
+		}
 	}
 	utils.WriteOKData(w, cards, map[string]any{"count": len(cards)})
 }
-
-// removed sanitizeFileBase: now lives in utils.UploadImage
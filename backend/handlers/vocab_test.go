@@ -3,6 +3,8 @@ package handlers_test
 import (
 	"bytes"
 	"encoding/json"
+	"image"
+	"image/png"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
@@ -11,13 +13,17 @@ import (
 	"strings"
 	"testing"
 
+	"learnlang-backend/blob"
+	"learnlang-backend/models"
 	"learnlang-backend/router"
 	"learnlang-backend/store"
 )
 
-// tinyPNG returns a minimal PNG header to satisfy content sniffing (image/png)
+// tinyPNG returns a valid 1x1 PNG; uploads are decoded, so a bare header is not enough
 func tinyPNG() []byte {
-	return []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 1, 1)))
+	return buf.Bytes()
 }
 
 func newMultipartVocabReq(t *testing.T, url, name, packID string) (*http.Request, error) {
@@ -53,6 +59,9 @@ func newMultipartVocabReq(t *testing.T, url, name, packID string) (*http.Request
 
 func TestCreateVocab_And_GetFlashcards(t *testing.T) {
 	os.Setenv("UPLOAD_DIR", t.TempDir())
+	if err := blob.Init(); err != nil {
+		t.Fatalf("blob init failed: %v", err)
+	}
 	if err := store.InitFromEnv(); err != nil {
 		t.Fatalf("db init failed: %v", err)
 	}
@@ -69,7 +78,7 @@ func TestCreateVocab_And_GetFlashcards(t *testing.T) {
 
 	// Add vocabs
 	// Find the created pack ID using composite key
-	packID := store.GetPackIDByKey("u1:1:kitchen")
+	packID := store.GetPackIDByKey("u1:1:3:kitchen")
 	if packID == "" {
 		t.Fatalf("pack not created")
 	}
@@ -123,6 +132,9 @@ func TestCreateVocab_And_GetFlashcards(t *testing.T) {
 
 func TestCreateVocab_Duplicate(t *testing.T) {
 	os.Setenv("UPLOAD_DIR", t.TempDir())
+	if err := blob.Init(); err != nil {
+		t.Fatalf("blob init failed: %v", err)
+	}
 	if err := store.InitFromEnv(); err != nil {
 		t.Fatalf("db init failed: %v", err)
 	}
@@ -139,7 +151,7 @@ func TestCreateVocab_Duplicate(t *testing.T) {
 
 	// Create vocab
 	// lookup packID via composite key for Sports/de/u1
-	sportID := store.GetPackIDByKey("u1:2:sports")
+	sportID := store.GetPackIDByKey("u1:2:3:sports")
 	if sportID == "" {
 		t.Fatalf("sports pack not found")
 	}
@@ -178,3 +190,254 @@ func TestCreateVocab_Duplicate(t *testing.T) {
 		t.Fatalf("expected 409, got %d body=%s", w2.Code, w2.Body.String())
 	}
 }
+
+func TestCreateVocab_InvalidPackStoresNoImage(t *testing.T) {
+	dir := t.TempDir()
+	os.Setenv("UPLOAD_DIR", dir)
+	if err := blob.Init(); err != nil {
+		t.Fatalf("blob init failed: %v", err)
+	}
+	if err := store.InitFromEnv(); err != nil {
+		t.Fatalf("db init failed: %v", err)
+	}
+	store.Reset()
+	h := router.NewRouter()
+
+	w := httptest.NewRecorder()
+	req, err := newMultipartVocabReq(t, "/api/vocabs", "ghost", "does-not-exist")
+	if err != nil {
+		t.Fatalf("multipart req err: %v", err)
+	}
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d body=%s", w.Code, w.Body.String())
+	}
+	// a rejected create must not leave an image behind
+	entries, _ := os.ReadDir(filepath.Join(dir, "images"))
+	if len(entries) != 0 {
+		t.Fatalf("expected no stored images, got %d", len(entries))
+	}
+}
+
+func TestCreateVocab_JSONImageReference(t *testing.T) {
+	h := setup(t)
+
+	store.CreatePack(models.Pack{ID: "p1", Name: "Kitchen", LangID: "1", UserID: "u1"}, "")
+	imagesDir := filepath.Join(os.Getenv("UPLOAD_DIR"), "images")
+	if err := os.MkdirAll(imagesDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(imagesDir, "knife.png"), tinyPNG(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(map[string]string{"name": "knife", "translation": "चाकू", "pack_id": "p1", "image": "/files/images/knife.png"})
+	req := httptest.NewRequest(http.MethodPost, "/api/vocabs", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	// Private addresses must never be fetched.
+	body, _ = json.Marshal(map[string]string{"name": "fork", "translation": "कांटा", "pack_id": "p1", "image": "http://127.0.0.1:8080/fork.png"})
+	req = httptest.NewRequest(http.MethodPost, "/api/vocabs", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "INVALID_IMAGE_URL") {
+		t.Fatalf("expected 400 INVALID_IMAGE_URL, got %d body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestCreateVocab_WithAudio(t *testing.T) {
+	h := setup(t)
+	store.CreatePack(models.Pack{ID: "p1", Name: "Kitchen", LangID: "1", UserID: "u1"}, "")
+
+	newReq := func(name string, audio []byte) *http.Request {
+		body := &bytes.Buffer{}
+		mw := multipart.NewWriter(body)
+		_ = mw.WriteField("name", name)
+		_ = mw.WriteField("translation", name)
+		_ = mw.WriteField("pack_id", "p1")
+		fw, _ := mw.CreateFormFile("image", "file.png")
+		_, _ = fw.Write(tinyPNG())
+		aw, _ := mw.CreateFormFile("audio", "clip.mp3")
+		_, _ = aw.Write(audio)
+		_ = mw.Close()
+		req := httptest.NewRequest(http.MethodPost, "/api/vocabs", body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		return req
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newReq("knife", []byte("ID3\x04\x00\x00 fake mp3")))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	var created struct {
+		Data models.Vocab `json:"data"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+	if len(created.Data.Audio) != 1 || !strings.HasPrefix(created.Data.Audio[0], "/files/audio/") {
+		t.Fatalf("expected one audio clip, got %v", created.Data.Audio)
+	}
+
+	fw := httptest.NewRecorder()
+	h.ServeHTTP(fw, httptest.NewRequest(http.MethodGet, created.Data.Audio[0], nil))
+	if fw.Code != http.StatusOK || fw.Header().Get("Content-Type") != "audio/mpeg" {
+		t.Fatalf("serve audio: %d %q", fw.Code, fw.Header().Get("Content-Type"))
+	}
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/api/flashcards?user_id=u1&lang_id=1", nil))
+	var cards struct {
+		Data []map[string]any `json:"data"`
+	}
+	_ = json.Unmarshal(rw.Body.Bytes(), &cards)
+	if len(cards.Data) != 1 || cards.Data[0]["audio"] != created.Data.Audio[0] {
+		t.Fatalf("expected flashcard audio, got %v", cards.Data)
+	}
+
+	// Non-audio content is rejected by sniffing, whatever the file name says.
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newReq("fork", []byte("<html><script>alert(1)</script>")))
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "INVALID_FILE_TYPE") {
+		t.Fatalf("expected 400 INVALID_FILE_TYPE, got %d body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestCreateVocab_WithDetails(t *testing.T) {
+	h := setup(t)
+	store.CreatePack(models.Pack{ID: "p1", Name: "Kitchen", LangID: "2", UserID: "u1"}, "")
+	imagesDir := filepath.Join(os.Getenv("UPLOAD_DIR"), "images")
+	if err := os.MkdirAll(imagesDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(imagesDir, "knife.png"), tinyPNG(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(map[string]any{
+		"name": "Messer", "translation": "knife", "pack_id": "p1", "image": "/files/images/knife.png",
+		"part_of_speech": "Noun", "notes": "neuter, plural unchanged", "tags": []string{"Cutlery", "kitchen"},
+		"examples": []map[string]string{{"text": "Das Messer ist scharf.", "translation": "The knife is sharp."}},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/vocabs", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	var pack struct {
+		Data struct {
+			Vocabs []models.Vocab `json:"vocabs"`
+		} `json:"data"`
+	}
+	pw := httptest.NewRecorder()
+	h.ServeHTTP(pw, httptest.NewRequest(http.MethodGet, "/api/packs/p1", nil))
+	_ = json.Unmarshal(pw.Body.Bytes(), &pack)
+	if len(pack.Data.Vocabs) != 1 {
+		t.Fatalf("expected one vocab in pack, got %s", pw.Body.String())
+	}
+	got := pack.Data.Vocabs[0]
+	if got.PartOfSpeech != "noun" || got.Notes != "neuter, plural unchanged" || len(got.Tags) != 2 || got.Tags[0] != "cutlery" ||
+		len(got.Examples) != 1 || got.Examples[0].Translation != "The knife is sharp." {
+		t.Fatalf("details not round-tripped: %+v", got)
+	}
+
+	for _, q := range []string{"q=scharf&user_id=u1", "q=messer&user_id=u1&tag=cutlery&pos=noun"} {
+		sw := httptest.NewRecorder()
+		h.ServeHTTP(sw, httptest.NewRequest(http.MethodGet, "/api/search?"+q, nil))
+		if sw.Code != http.StatusOK || !strings.Contains(sw.Body.String(), got.ID) {
+			t.Fatalf("search %q: %d %s", q, sw.Code, sw.Body.String())
+		}
+	}
+
+	body, _ = json.Marshal(map[string]any{"name": "Gabel", "translation": "fork", "pack_id": "p1", "image": "/files/images/knife.png", "part_of_speech": "gerundive"})
+	req = httptest.NewRequest(http.MethodPost, "/api/vocabs", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid part_of_speech, got %d", w.Code)
+	}
+}
+
+func TestCreateVocab_DuplicateAfterFolding(t *testing.T) {
+	h := setup(t)
+	store.CreatePack(models.Pack{ID: "p-de", Name: "Stadt", LangID: "2", UserID: "u1"}, "")
+	store.CreatePack(models.Pack{ID: "p-hi", Name: "Shabd", LangID: "1", UserID: "u1"}, "")
+	imagesDir := filepath.Join(os.Getenv("UPLOAD_DIR"), "images")
+	if err := os.MkdirAll(imagesDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(imagesDir, "x.png"), tinyPNG(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	create := func(packID, name string) int {
+		t.Helper()
+		body, _ := json.Marshal(map[string]string{"name": name, "translation": "x", "pack_id": packID, "image": "/files/images/x.png"})
+		req := httptest.NewRequest(http.MethodPost, "/api/vocabs", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	for _, tc := range []struct {
+		pack, first, second string
+	}{
+		{"p-de", "Straße", "STRASSE"},
+		{"p-de", "Müller", "Müller"}, // NFC vs NFD
+		{"p-hi", "ज़रूर", "जरूर"},     // nukta
+	} {
+		if code := create(tc.pack, tc.first); code != http.StatusCreated {
+			t.Fatalf("create %q: %d", tc.first, code)
+		}
+		if code := create(tc.pack, tc.second); code != http.StatusConflict {
+			t.Errorf("create %q after %q: expected 409, got %d", tc.second, tc.first, code)
+		}
+	}
+
+	// Renaming onto a folded name is caught by the unique index.
+	store.CreateVocab(models.Vocab{ID: "v-weg", Image: "/files/images/x.png", Name: "Weg", Translation: "way", PackID: "p-de"}, "")
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	_ = mw.WriteField("name", "STRASSE")
+	_ = mw.Close()
+	req := httptest.NewRequest(http.MethodPut, "/api/vocabs/v-weg", body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusConflict {
+		t.Errorf("rename onto Straße: expected 409, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGetFlashcards_SourceLanguage(t *testing.T) {
+	h := setup(t)
+	store.CreatePack(models.Pack{ID: "p-en", Name: "Kitchen", LangID: "1", UserID: "u1"}, "")
+	store.CreatePack(models.Pack{ID: "p-de", Name: "Küche", LangID: "1", SourceLangID: "2", UserID: "u1"}, "")
+	store.CreateVocab(models.Vocab{ID: "v1", Image: "/files/images/a.png", Name: "चाकू", Translation: "knife", PackID: "p-en"}, "")
+	store.CreateVocab(models.Vocab{ID: "v2", Image: "/files/images/b.png", Name: "कांटा", Translation: "Gabel", PackID: "p-de"}, "")
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/flashcards?user_id=u1&lang_id=1&source_lang_id=2", nil))
+	var cards struct {
+		Data []map[string]any `json:"data"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &cards)
+	if w.Code != http.StatusOK || len(cards.Data) != 1 || cards.Data[0]["id"] != "v2" {
+		t.Fatalf("expected only the German-translated card, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/flashcards?user_id=u1&lang_id=1&source_lang_id=zz", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown source language, got %d", w.Code)
+	}
+}
@@ -1,11 +1,23 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"flag"
 	"log"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"learnlang-backend/blob"
+	"learnlang-backend/dictionary"
+	"learnlang-backend/gc"
 	"learnlang-backend/router"
+	"learnlang-backend/speech"
 	"learnlang-backend/store"
+	"learnlang-backend/translate"
 	"learnlang-backend/utils"
 )
 
@@ -13,9 +25,54 @@ func main() {
 	if err := store.InitFromEnv(); err != nil {
 		log.Fatalf("failed to init database: %v", err)
 	}
-	if err := utils.VerifyUploadDirWritable(); err != nil {
-		log.Fatalf("upload dir check failed: %v", err)
+	if err := blob.Init(); err != nil {
+		log.Fatalf("blob store config invalid: %v", err)
 	}
+	if blob.IsLocal() {
+		if err := utils.VerifyUploadDirWritable(); err != nil {
+			log.Fatalf("upload dir check failed: %v", err)
+		}
+	}
+	if len(os.Args) > 1 && os.Args[1] == "gc" {
+		runGC(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "dict-import" {
+		runDictImport(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "backfill-names" {
+		runBackfillNames()
+		return
+	}
+
+	// Periodic orphaned image cleanup, e.g. GC_INTERVAL=24h
+	if v := os.Getenv("GC_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil || interval <= 0 {
+			log.Fatalf("invalid GC_INTERVAL %q", v)
+		}
+		opts := gc.OptionsFromEnv()
+		if err := opts.Validate(); err != nil {
+			log.Fatalf("invalid gc config: %v", err)
+		}
+		gc.StartBackground(context.Background(), interval, opts)
+	}
+	// Periodic pronunciation backfill for vocabs without audio, e.g. TTS_INTERVAL=1h
+	if v := os.Getenv("TTS_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil || interval <= 0 {
+			log.Fatalf("invalid TTS_INTERVAL %q", v)
+		}
+		p, err := speech.FromEnv()
+		if err != nil || p == nil {
+			log.Fatalf("TTS_INTERVAL requires a valid TTS_PROVIDER: %v", err)
+		}
+		speech.SetDefault(p)
+		speech.StartBackground(context.Background(), p, interval, 50)
+	}
+	// Configure translation suggestions up front, not on first use
+	translate.Default()
 	r := router.NewRouter()
 
 	log.Println("Server running on :8080")
@@ -23,3 +80,80 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// runGC implements `go run . gc [-dry-run] [-grace 24h] [-quarantine DIR]`
+// and prints the report as JSON.
+func runGC(args []string) {
+	defaults := gc.OptionsFromEnv()
+	fs := flag.NewFlagSet("gc", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "report orphaned images without removing them")
+	grace := fs.Duration("grace", defaults.Grace, "only collect images older than this")
+	quarantine := fs.String("quarantine", defaults.QuarantineDir, "copy orphans here before deleting them")
+	_ = fs.Parse(args)
+
+	rep, err := gc.Run(context.Background(), gc.Options{Grace: *grace, DryRun: *dryRun, QuarantineDir: *quarantine})
+	if err != nil {
+		log.Fatalf("gc failed: %v", err)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(rep)
+	if len(rep.Errors) > 0 {
+		os.Exit(1)
+	}
+}
+
+// runDictImport implements `go run . dict-import -lang 2 -source-lang 3
+// [-format tsv|xml] [-source NAME] FILE` and prints the import report.
+func runDictImport(args []string) {
+	fs := flag.NewFlagSet("dict-import", flag.ExitOnError)
+	langID := fs.String("lang", "", "language ID of the headwords")
+	sourceLangID := fs.String("source-lang", "", "language ID of the definitions")
+	format := fs.String("format", "", "dump format: tsv or xml (default: from file extension)")
+	source := fs.String("source", "", "name recorded on each entry (default: file name)")
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 || *langID == "" || *sourceLangID == "" {
+		log.Fatal("usage: dict-import -lang ID -source-lang ID [-format tsv|xml] [-source NAME] FILE")
+	}
+
+	path := fs.Arg(0)
+	f := dictionary.Format(*format)
+	if f == "" {
+		var err error
+		if f, err = dictionary.FormatFromPath(path); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *source == "" {
+		*source = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("dict-import: %v", err)
+	}
+	defer file.Close()
+
+	rep, err := dictionary.Import(context.Background(), file, f, dictionary.Options{LangID: *langID, SourceLangID: *sourceLangID, Source: *source})
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(rep)
+	if err != nil {
+		log.Fatalf("dict-import failed: %v", err)
+	}
+}
+
+// runBackfillNames implements `go run . backfill-names`: it folds the names of
+// vocabs stored before vocabs.name_norm existed and prints a report. Run it
+// once after upgrading; vocabs written since then are folded on write.
+func runBackfillNames() {
+	rep, err := store.BackfillNameNorm()
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(rep)
+	if err != nil {
+		log.Fatalf("backfill-names failed: %v", err)
+	}
+	if len(rep.Conflicts) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,13 @@
+package models
+
+// DictionaryEntry is a headword from an imported bilingual dictionary.
+type DictionaryEntry struct {
+	ID           int64    `json:"id"`
+	LangID       string   `json:"lang_id"`        // language of the headword
+	SourceLangID string   `json:"source_lang_id"` // language of the definitions
+	Headword     string   `json:"headword"`
+	PartOfSpeech string   `json:"part_of_speech,omitempty"` // see utils.PartsOfSpeech
+	Gender       string   `json:"gender,omitempty"`         // as in Grammar.Gender
+	Definitions  []string `json:"definitions"`
+	Source       string   `json:"source,omitempty"` // dump the entry was imported from
+}
@@ -4,4 +4,10 @@ type Language struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
 	Code string `json:"code"` // e.g. "hi" for Hindi
+
+	NativeName string `json:"native_name"` // e.g. "हिन्दी"
+	Script     string `json:"script"`      // ISO 15924, e.g. "Deva"
+	Direction  string `json:"direction"`   // "ltr" or "rtl"
+	Collation  string `json:"collation"`   // BCP 47 locale used for sorting, e.g. "de-DE"
+	Enabled    bool   `json:"enabled"`
 }
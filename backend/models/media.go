@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Media is a stored, content-addressed file (currently images).
+type Media struct {
+	Hash        string    `json:"hash"` // hex SHA-256 of the stored bytes
+	Path        string    `json:"path"` // public URL path under /files/
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+	// LastUploadedAt is bumped by every upload of the same bytes.
+	LastUploadedAt time.Time `json:"last_uploaded_at"`
+}
@@ -1,9 +1,22 @@
 package models
 
+import "time"
+
 type Pack struct {
-	ID     string `json:"id"`
-	Name   string `json:"name"`    // Unique per user per language
-	LangID string `json:"lang_id"` // foreign key to language.ID
-	UserID string `json:"user_id"` // Optional for MVP
-	Public bool   `json:"public"`  // Whether the pack is public or private
+	ID           string `json:"id"`
+	Name         string `json:"name"`           // Unique per user per language
+	LangID       string `json:"lang_id"`        // foreign key to language.ID; the language being learned
+	SourceLangID string `json:"source_lang_id"` // foreign key to language.ID; the language of the translations
+	UserID       string `json:"user_id"`        // Optional for MVP
+	Public       bool   `json:"public"`         // Whether the pack is public or private
+}
+
+// PublicPack is a catalogue entry for a public pack with its owner and popularity stats.
+type PublicPack struct {
+	Pack
+	OwnerName  string    `json:"owner_name"`  // users.name, falls back to user_id
+	VocabCount int       `json:"vocab_count"` // number of vocabs in the pack
+	Forks      int       `json:"forks"`       // packs forked from this one
+	Learners   int       `json:"learners"`    // distinct users who forked this pack
+	CreatedAt  time.Time `json:"created_at"`
 }
@@ -1,9 +1,82 @@
 package models
 
+import "time"
+
 type Vocab struct {
 	ID          string `json:"id"`
 	Image       string `json:"image"`
 	Name        string `json:"name"`
 	Translation string `json:"translation"`
 	PackID      string `json:"pack_id"` // foreign key to Pack.ID
+
+	// Translations lists every accepted translation, primary (Translation) first.
+	Translations []string `json:"translations,omitempty"`
+	// Synonyms are other words for Name in the same language.
+	Synonyms []string `json:"synonyms,omitempty"`
+
+	// Optional details
+	Examples     []Example `json:"examples,omitempty"`
+	Notes        string    `json:"notes,omitempty"`
+	PartOfSpeech string    `json:"part_of_speech,omitempty"` // see utils.PartsOfSpeech
+	Tags         []string  `json:"tags,omitempty"`
+	Grammar      *Grammar  `json:"grammar,omitempty"` // see utils.NormalizeGrammar
+
+	// Difficulty is rated by the user from 1 (easy) to 5 (hard); 0 means unrated.
+	Difficulty int       `json:"difficulty,omitempty"`
+	CreatedAt  time.Time `json:"created_at,omitzero"`
+
+	// Transliteration romanises the Devanagari side of a Hindi vocab; see utils.AutoTransliteration.
+	Transliteration string `json:"transliteration,omitempty"`
+
+	// Images lists all image URLs in display order; Image is the primary one.
+	Images []string `json:"images,omitempty"`
+
+	// Audio lists pronunciation clip URLs (/files/audio/...) in display order.
+	Audio []string `json:"audio,omitempty"`
+
+	// ImageVariants maps variant name (thumb, card, full) to URL; filled in responses only.
+	ImageVariants map[string]string `json:"image_variants,omitempty"`
+}
+
+// Example is a sentence using a vocab, with an optional translation.
+type Example struct {
+	Text        string `json:"text"`
+	Translation string `json:"translation,omitempty"`
+}
+
+// Grammar holds language-specific grammatical attributes. Which fields a
+// language accepts is decided by utils.NormalizeGrammar.
+type Grammar struct {
+	// Nouns
+	Gender string `json:"gender,omitempty"` // masculine, feminine, neuter
+	Plural string `json:"plural,omitempty"`
+
+	// Verbs (German principal parts: fahren, fährt, fuhr, ist gefahren)
+	SeparablePrefix string `json:"separable_prefix,omitempty"` // "an" in anrufen
+	Present         string `json:"present,omitempty"`          // 3rd person singular
+	Preterite       string `json:"preterite,omitempty"`
+	PastParticiple  string `json:"past_participle,omitempty"`
+	Auxiliary       string `json:"auxiliary,omitempty"` // haben or sein
+}
+
+// VocabSearchResult is a ranked vocab match together with its owning pack.
+type VocabSearchResult struct {
+	Vocab
+	PackName   string  `json:"pack_name"`
+	PackUserID string  `json:"pack_user_id"`
+	PackPublic bool    `json:"pack_public"`
+	Rank       float64 `json:"rank"`
+}
+
+// DuplicateVocab is one member of a cross-pack duplicate group.
+type DuplicateVocab struct {
+	Vocab
+	PackName string `json:"pack_name"`
+	LinkedTo string `json:"linked_to,omitempty"` // canonical vocab ID if linked
+}
+
+// DuplicateGroup lists vocabs sharing the same case/diacritic-insensitive name.
+type DuplicateGroup struct {
+	Key    string           `json:"key"`
+	Vocabs []DuplicateVocab `json:"vocabs"`
 }
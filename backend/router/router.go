@@ -5,7 +5,6 @@ import (
 	"strings"
 
 	"learnlang-backend/handlers"
-	"learnlang-backend/utils"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -23,16 +22,16 @@ func NewRouter() http.Handler {
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:3000", "https://localhost:3000", "https://learnlang.app", "https://www.learnlang.app"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "If-None-Match"},
+		ExposedHeaders:   []string{"Link", "ETag"},
 		AllowCredentials: false,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
 	}))
 
-	// Static uploads (served from local disk)
-	fs := http.StripPrefix("/files/", http.FileServer(http.Dir(utils.UploadDir())))
-	// fs := http.FileServer(http.Dir(utils.UploadDir()))
-	r.Handle("/files/*", intercept(fs))
+	// Uploaded files (proxied from the blob store or redirected to a presigned URL)
+	r.Handle("/files/*", intercept(http.HandlerFunc(handlers.FilesHandler)))
+	// Resized image variants, generated on demand when missing
+	r.Get("/files/images/{variant}/{name}", handlers.ImageVariantHandler)
 
 	// Routes
 	r.Route("/api", func(r chi.Router) {
@@ -42,10 +41,33 @@ func NewRouter() http.Handler {
 		r.Post("/packs", handlers.CreatePackHandler)
 		r.Get("/packs/{id}", handlers.GetPackByIDHandler)
 
+		r.Get("/public/packs", handlers.GetPublicPacksHandler)
+
 		r.Post("/vocabs", handlers.CreateVocabHandler)
 		r.Put("/vocabs/{id}", handlers.UpdateVocabHandler)
+		r.Get("/vocabs/{id}/pronunciation", handlers.GetPronunciationHandler)
+		r.Post("/vocabs/{id}/images", handlers.AddVocabImagesHandler)
+		r.Put("/vocabs/{id}/images", handlers.SetVocabImagesHandler)
+		r.Get("/vocabs/duplicates", handlers.GetDuplicateVocabsHandler)
+		r.Post("/vocabs/merge", handlers.MergeVocabsHandler)
+		r.Post("/vocabs/move", handlers.MoveVocabsHandler)
+		r.Post("/vocabs/copy", handlers.CopyVocabsHandler)
+		r.Post("/vocabs/batch", handlers.BatchVocabsHandler)
 
 		r.Get("/flashcards", handlers.GetFlashcardsHandler)
+		r.Post("/flashcards/check", handlers.CheckAnswerHandler)
+
+		r.Get("/search", handlers.SearchVocabsHandler)
+		r.Get("/suggest/translation", handlers.SuggestTranslationHandler)
+		r.Get("/dictionary", handlers.GetDictionaryHandler)
+
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(handlers.RequireAdmin)
+			r.Get("/languages", handlers.AdminListLanguagesHandler)
+			r.Post("/languages", handlers.AdminCreateLanguageHandler)
+			r.Put("/languages/{id}", handlers.AdminUpdateLanguageHandler)
+			r.Delete("/languages/{id}", handlers.AdminDisableLanguageHandler)
+		})
 	})
 
 	return r
@@ -0,0 +1,113 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Command runs a locally installed TTS engine. Args may contain the
+// placeholders {text}, {lang} and {out}; the engine must write the audio to
+// {out}. No shell is involved, so text is never interpreted by a shell; text
+// starting with "-" is refused so the engine cannot read it as an option.
+//
+// Example (eSpeak NG): espeak-ng -v {lang} -w {out} -- {text}
+type Command struct {
+	Path        string
+	Args        []string
+	ContentType string            // type of the produced file, e.g. audio/wav
+	Voices      map[string]string // optional language code -> engine voice
+}
+
+// NewCommandFromEnv builds a Command from TTS_COMMAND (space-separated, no
+// quoting), TTS_CONTENT_TYPE (default audio/wav) and TTS_VOICES
+// (comma-separated lang=voice pairs, e.g. "hi=hi,de=de+m3").
+func NewCommandFromEnv() (*Command, error) {
+	fields := strings.Fields(os.Getenv("TTS_COMMAND"))
+	if len(fields) == 0 {
+		return nil, errors.New("TTS_COMMAND must be set for TTS_PROVIDER=command")
+	}
+	path, err := exec.LookPath(fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("tts engine: %w", err)
+	}
+	c := &Command{Path: path, Args: fields[1:], ContentType: os.Getenv("TTS_CONTENT_TYPE"), Voices: map[string]string{}}
+	if c.ContentType == "" {
+		c.ContentType = "audio/wav"
+	}
+	for _, pair := range strings.Split(os.Getenv("TTS_VOICES"), ",") {
+		if lang, voice, ok := strings.Cut(strings.TrimSpace(pair), "="); ok {
+			c.Voices[lang] = voice
+		}
+	}
+	return c, nil
+}
+
+// Synthesize runs the engine with a temporary output file.
+func (c *Command) Synthesize(ctx context.Context, text, lang string) ([]byte, string, error) {
+	if strings.HasPrefix(text, "-") {
+		return nil, "", fmt.Errorf("refusing to synthesize %q: text must not start with \"-\"", text)
+	}
+	dir, err := os.MkdirTemp("", "tts-*")
+	if err != nil {
+		return nil, "", err
+	}
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "out")
+	voice := lang
+	if v, ok := c.Voices[lang]; ok {
+		voice = v
+	}
+	r := strings.NewReplacer("{text}", text, "{lang}", voice, "{out}", out)
+	args := make([]string, len(c.Args))
+	for i, a := range c.Args {
+		args[i] = r.Replace(a)
+	}
+	cmd := exec.CommandContext(ctx, c.Path, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, "", fmt.Errorf("%s: %w: %s", filepath.Base(c.Path), err, strings.TrimSpace(stderr.String()))
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		return nil, "", fmt.Errorf("read engine output: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, "", errors.New("engine produced no audio")
+	}
+	return data, c.ContentType, nil
+}
+
+// Fake returns a tiny WAV file per request and records what it was asked for.
+type Fake struct {
+	mu    sync.Mutex
+	Calls []string // "lang:text"
+	Err   error    // returned instead of audio when set
+}
+
+// Synthesize returns deterministic audio that differs by text and language.
+func (f *Fake) Synthesize(_ context.Context, text, lang string) ([]byte, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Calls = append(f.Calls, lang+":"+text)
+	if f.Err != nil {
+		return nil, "", f.Err
+	}
+	payload := []byte(lang + ":" + text)
+	wav := append([]byte("RIFF\x00\x00\x00\x00WAVE"), payload...)
+	return wav, "audio/wav", nil
+}
+
+// CallCount reports how many times Synthesize ran.
+func (f *Fake) CallCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.Calls)
+}
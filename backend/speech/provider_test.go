@@ -0,0 +1,57 @@
+package speech
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestCommand_Synthesize(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	// Stand-in engine: writes a WAV header followed by its voice and text arguments.
+	c := &Command{
+		Path:        sh,
+		Args:        []string{"-c", `printf 'RIFF0000WAVE%s|%s' "$1" "$2" > "$3"`, "engine", "{lang}", "{text}", "{out}"},
+		ContentType: "audio/wav",
+		Voices:      map[string]string{"de": "de+m3"},
+	}
+	text := "Haus; rm -rf /"
+	audio, ct, err := c.Synthesize(context.Background(), text, "de")
+	if err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if ct != "audio/wav" || string(audio) != "RIFF0000WAVEde+m3|"+text {
+		t.Fatalf("got %q %q", ct, audio)
+	}
+
+	if _, _, err := c.Synthesize(context.Background(), "-w/etc/x", "de"); err == nil {
+		t.Fatal("expected text starting with '-' to be refused")
+	}
+
+	c.Args = []string{"-c", "echo broken >&2; exit 3"}
+	if _, _, err := c.Synthesize(context.Background(), "x", "hi"); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Fatalf("expected engine error with stderr, got %v", err)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("TTS_PROVIDER", "")
+	if p, err := FromEnv(); p != nil || err != nil {
+		t.Fatalf("expected disabled, got %v %v", p, err)
+	}
+	t.Setenv("TTS_PROVIDER", "fake")
+	if p, err := FromEnv(); err != nil {
+		t.Fatal(err)
+	} else if _, ok := p.(*Fake); !ok {
+		t.Fatalf("expected *Fake, got %T", p)
+	}
+	t.Setenv("TTS_PROVIDER", "command")
+	t.Setenv("TTS_COMMAND", "")
+	if p, err := FromEnv(); err == nil || p != nil {
+		t.Fatalf("expected nil provider and error without TTS_COMMAND, got %v %v", p, err)
+	}
+}
@@ -0,0 +1,169 @@
+// Package speech generates pronunciation audio for vocab names with a
+// pluggable text-to-speech engine and caches the results in the blob store.
+package speech
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"learnlang-backend/models"
+	"learnlang-backend/store"
+	"learnlang-backend/utils"
+)
+
+// ErrDisabled is returned when no TTS provider is configured.
+var ErrDisabled = errors.New("text-to-speech is not configured")
+
+// TTSProvider synthesizes speech for text in a language (ISO code such as
+// "hi" or "de", as stored in languages.code).
+type TTSProvider interface {
+	Synthesize(ctx context.Context, text, lang string) (audio []byte, contentType string, err error)
+}
+
+var (
+	defaultMu       sync.Mutex
+	defaultSet      bool
+	defaultProvider TTSProvider
+)
+
+// Default returns the provider configured by FromEnv, or nil if TTS is
+// disabled. Invalid configuration is logged once and treated as disabled.
+func Default() TTSProvider {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if !defaultSet {
+		p, err := FromEnv()
+		if err != nil {
+			log.Printf("tts disabled: %v", err)
+		}
+		defaultProvider, defaultSet = p, true
+	}
+	return defaultProvider
+}
+
+// SetDefault overrides the process-wide provider (tests, custom wiring).
+// Passing nil disables TTS.
+func SetDefault(p TTSProvider) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultProvider, defaultSet = p, true
+}
+
+// FromEnv builds a provider from TTS_PROVIDER: "" (disabled), "command" (see
+// NewCommandFromEnv) or "fake".
+func FromEnv() (TTSProvider, error) {
+	switch strings.ToLower(os.Getenv("TTS_PROVIDER")) {
+	case "":
+		return nil, nil
+	case "command":
+		c, err := NewCommandFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	case "fake":
+		return &Fake{}, nil
+	default:
+		return nil, fmt.Errorf("unknown TTS_PROVIDER %q", os.Getenv("TTS_PROVIDER"))
+	}
+}
+
+// Pronounce returns the public URL of a pronunciation of text in lang,
+// synthesizing and caching it on first use.
+func Pronounce(ctx context.Context, p TTSProvider, lang, text string) (string, error) {
+	if p == nil {
+		return "", ErrDisabled
+	}
+	text = strings.TrimSpace(text)
+	if text == "" || lang == "" {
+		return "", fmt.Errorf("text and language are required")
+	}
+	if url, ok := store.GetTTSAudio(lang, text); ok {
+		return url, nil
+	}
+	audio, contentType, err := p.Synthesize(ctx, text, lang)
+	if err != nil {
+		return "", fmt.Errorf("synthesize: %w", err)
+	}
+	if sniffed, ok := utils.SniffAudio(audio); ok {
+		contentType = sniffed
+	}
+	f, err := utils.UploadAudio(contentType, audio, len(audio), bytes.NewReader(nil))
+	if err != nil {
+		return "", fmt.Errorf("save audio: %w", err)
+	}
+	if err := store.RecordMedia(models.Media{Hash: f.Hash, Path: f.URL, ContentType: f.ContentType, Size: f.Size}); err != nil {
+		log.Printf("record media %s: %v", f.URL, err)
+	}
+	if err := store.SaveTTSAudio(lang, text, f.URL); err != nil {
+		return "", fmt.Errorf("cache audio: %w", err)
+	}
+	// A concurrent request may have cached first; serve what the cache holds.
+	if cached, ok := store.GetTTSAudio(lang, text); ok {
+		return cached, nil
+	}
+	return f.URL, nil
+}
+
+// PronounceVocab returns the pronunciation URL for a vocab's name in its pack's language.
+func PronounceVocab(ctx context.Context, p TTSProvider, vocabID string) (string, error) {
+	s, ok := store.GetVocabSpeech(vocabID)
+	if !ok {
+		return "", fmt.Errorf("unknown vocab %q", vocabID)
+	}
+	return Pronounce(ctx, p, s.LangCode, s.Text)
+}
+
+// Backfill synthesizes audio for up to limit vocabs that have none yet and
+// returns how many were generated.
+func Backfill(ctx context.Context, p TTSProvider, limit int) (int, error) {
+	if p == nil {
+		return 0, ErrDisabled
+	}
+	todo, err := store.VocabsMissingSpeech(limit)
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	for _, s := range todo {
+		if ctx.Err() != nil {
+			return n, ctx.Err()
+		}
+		if _, err := Pronounce(ctx, p, s.LangCode, s.Text); err != nil {
+			log.Printf("tts for vocab %s: %v", s.VocabID, err)
+			continue
+		}
+		n++
+	}
+	return n, nil
+}
+
+// StartBackground runs Backfill every interval until ctx is cancelled.
+func StartBackground(ctx context.Context, p TTSProvider, interval time.Duration, batch int) {
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				n, err := Backfill(ctx, p, batch)
+				if err != nil {
+					log.Printf("tts backfill: %v", err)
+					continue
+				}
+				if n > 0 {
+					log.Printf("tts backfill: generated %d pronunciations", n)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// appendVocabPaths appends paths to an ordered (vocab_id, path, position)
+// table, skipping paths the vocab already has.
+func appendVocabPaths(table, vocabID string, paths []string) error {
+	if db == nil || len(paths) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := appendVocabPathsTx(ctx, tx, table, vocabID, paths); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// appendVocabPathsTx is appendVocabPaths inside the caller's transaction.
+func appendVocabPathsTx(ctx context.Context, tx *sql.Tx, table, vocabID string, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	var next int
+	if err := tx.QueryRowContext(ctx, `SELECT COALESCE(max(position)+1, 0) FROM `+table+` WHERE vocab_id=$1`, vocabID).Scan(&next); err != nil {
+		return err
+	}
+	for _, p := range paths {
+		res, err := tx.ExecContext(ctx, `INSERT INTO `+table+` (vocab_id, path, position) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`, vocabID, p, next)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			next++
+		}
+	}
+	return nil
+}
+
+// removeVocabPaths deletes the given paths of a vocab from an ordered table
+// inside the caller's transaction.
+func removeVocabPaths(ctx context.Context, tx *sql.Tx, table, vocabID string, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	in, args := inClause(paths, 2)
+	_, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE vocab_id=$1 AND path IN (`+in+`)`, append([]any{vocabID}, args...)...)
+	return err
+}
+
+// VocabAudio returns the ordered audio clip paths for each of the given vocabs.
+func VocabAudio(vocabIDs []string) map[string][]string {
+	return listVocabPaths("vocab_audio", vocabIDs)
+}
+
+// listVocabPaths reads an ordered (vocab_id, path, position) table.
+func listVocabPaths(table string, vocabIDs []string) map[string][]string {
+	out := map[string][]string{}
+	if db == nil || len(vocabIDs) == 0 {
+		return out
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	in, args := inClause(vocabIDs, 1)
+	rows, err := db.QueryContext(ctx, `SELECT vocab_id, path FROM `+table+` WHERE vocab_id IN (`+in+`) ORDER BY vocab_id, position, created_at`, args...)
+	if err != nil {
+		return out
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, p string
+		if err := rows.Scan(&id, &p); err == nil {
+			out[id] = append(out[id], p)
+		}
+	}
+	return out
+}
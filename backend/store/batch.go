@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"learnlang-backend/models"
+)
+
+// Batch operation kinds.
+const (
+	OpCreate = "create"
+	OpUpdate = "update"
+	OpDelete = "delete"
+)
+
+// VocabOp is a single validated write applied by ApplyVocabOps. Create and
+// update write every column of Vocab, details included; for OpDelete only
+// Vocab.ID is used.
+type VocabOp struct {
+	Kind  string
+	Vocab models.Vocab
+}
+
+// ApplyVocabOps executes ops in order inside one transaction. If any op fails
+// the whole batch is rolled back and the index of the failing op is returned.
+func ApplyVocabOps(ops []VocabOp) (int, error) {
+	if db == nil {
+		return -1, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return -1, err
+	}
+	defer tx.Rollback()
+
+	for i, op := range ops {
+		v := op.Vocab
+		var err error
+		switch op.Kind {
+		case OpCreate:
+			err = insertVocab(ctx, tx, v)
+		case OpUpdate:
+			err = updateVocab(ctx, tx, v)
+		case OpDelete:
+			_, err = tx.ExecContext(ctx, `DELETE FROM vocabs WHERE id=$1`, v.ID)
+		default:
+			err = fmt.Errorf("unknown op %q", op.Kind)
+		}
+		if err != nil {
+			return i, err
+		}
+	}
+	return -1, tx.Commit()
+}
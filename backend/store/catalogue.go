@@ -0,0 +1,120 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"learnlang-backend/models"
+)
+
+// Sort orders supported by ListPublicPacks.
+const (
+	SortPopular = "popular"
+	SortRecent  = "recent"
+	SortName    = "name"
+)
+
+// PublicPackFilter narrows and orders the public pack catalogue.
+type PublicPackFilter struct {
+	Query        string // case-insensitive substring match on pack name
+	LangID       string // optional language filter
+	SourceLangID string // optional filter on the language of the translations
+	Sort         string // one of SortPopular, SortRecent, SortName (default SortPopular)
+	Limit        int
+	Offset       int
+}
+
+// ListPublicPacks returns public packs only, with owner display names and popularity stats.
+func ListPublicPacks(f PublicPackFilter) []models.PublicPack {
+	if db == nil {
+		return []models.PublicPack{}
+	}
+	query := `SELECT p.id, p.name, p.lang_id, p.source_lang_id, p.user_id, p.public,
+                     COALESCE(u.name, p.user_id) AS owner_name,
+                     (SELECT count(*) FROM vocabs v WHERE v.pack_id = p.id) AS vocab_count,
+                     (SELECT count(*) FROM packs f WHERE f.forked_from = p.id) AS forks,
+                     (SELECT count(DISTINCT f.user_id) FROM packs f WHERE f.forked_from = p.id AND f.user_id <> p.user_id) AS learners,
+                     p.created_at
+              FROM packs p
+              LEFT JOIN users u ON u.id = p.user_id
+              WHERE p.public`
+	var args []any
+	if f.Query != "" {
+		args = append(args, "%"+escapeLike(f.Query)+"%")
+		query += fmt.Sprintf(" AND p.name ILIKE $%d", len(args))
+	}
+	if f.LangID != "" {
+		args = append(args, f.LangID)
+		query += fmt.Sprintf(" AND p.lang_id = $%d", len(args))
+	}
+	if f.SourceLangID != "" {
+		args = append(args, f.SourceLangID)
+		query += fmt.Sprintf(" AND p.source_lang_id = $%d", len(args))
+	}
+	switch f.Sort {
+	case SortRecent:
+		query += " ORDER BY p.created_at DESC, p.name"
+	case SortName:
+		query += " ORDER BY p.name, p.id"
+	default:
+		query += " ORDER BY forks DESC, learners DESC, vocab_count DESC, p.name"
+	}
+	if f.Limit > 0 {
+		args = append(args, f.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if f.Offset > 0 {
+		args = append(args, f.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return []models.PublicPack{}
+	}
+	defer rows.Close()
+	out := []models.PublicPack{}
+	for rows.Next() {
+		var p models.PublicPack
+		if err := rows.Scan(&p.ID, &p.Name, &p.LangID, &p.SourceLangID, &p.UserID, &p.Public, &p.OwnerName, &p.VocabCount, &p.Forks, &p.Learners, &p.CreatedAt); err == nil {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// SaveUserName sets the display name shown as owner_name in the catalogue
+// for a user without one. An existing name is never overwritten, so a request
+// naming someone else's user_id cannot rename them.
+func SaveUserName(id, name string) error {
+	if db == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := db.ExecContext(ctx, `INSERT INTO users (id, name) VALUES ($1, $2)
+		ON CONFLICT (id) DO NOTHING`, id, name)
+	return err
+}
+
+// MarkForked records that pack id was forked from another user's pack. The
+// first source wins, so copying from several public packs keeps the lineage stable.
+func MarkForked(id, fromID string) error {
+	if db == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := db.ExecContext(ctx, `UPDATE packs SET forked_from = $2 WHERE id = $1 AND forked_from IS NULL AND id <> $2`, id, fromID)
+	return err
+}
+
+// escapeLike escapes LIKE/ILIKE wildcards so user input matches literally.
+func escapeLike(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return r.Replace(s)
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"encoding/json"
+	"strings"
+
+	"learnlang-backend/models"
+)
+
+// vocabDetailCols selects the optional vocab fields, in the order of vocabDetails.dest.
+var vocabDetailCols = vocabDetailColsOf("")
+
+// vocabDetailColsOf is vocabDetailCols with each column qualified by a table
+// alias (e.g. "v."), for queries that join tables sharing column names.
+func vocabDetailColsOf(alias string) string {
+	cols := []string{"notes", "part_of_speech", "tags::text", "examples::text", "grammar::text", "transliteration",
+		"alternate_translations::text", "synonyms::text", "difficulty", "created_at"}
+	for i, c := range cols {
+		cols[i] = alias + c
+	}
+	return strings.Join(cols, ", ")
+}
+
+// vocabDetails scans the JSONB detail columns of a vocab row.
+type vocabDetails struct {
+	tags, examples, grammar string
+	alternates, synonyms    string
+}
+
+func (d *vocabDetails) dest(v *models.Vocab) []any {
+	return []any{&v.Notes, &v.PartOfSpeech, &d.tags, &d.examples, &d.grammar, &v.Transliteration, &d.alternates, &d.synonyms, &v.Difficulty, &v.CreatedAt}
+}
+
+func (d *vocabDetails) apply(v *models.Vocab) {
+	_ = json.Unmarshal([]byte(d.tags), &v.Tags)
+	_ = json.Unmarshal([]byte(d.examples), &v.Examples)
+	var alternates []string
+	_ = json.Unmarshal([]byte(d.alternates), &alternates)
+	if v.Translation != "" {
+		v.Translations = append([]string{v.Translation}, alternates...)
+	}
+	_ = json.Unmarshal([]byte(d.synonyms), &v.Synonyms)
+	var g models.Grammar
+	if json.Unmarshal([]byte(d.grammar), &g) == nil && g != (models.Grammar{}) {
+		v.Grammar = &g
+	}
+}
+
+// encodedDetails holds the JSONB columns of a vocab as JSON text.
+type encodedDetails struct {
+	tags, examples, grammar, alternates, synonyms string
+}
+
+// encodeVocabDetails serializes the JSONB detail columns. Translations are
+// stored without the primary, which lives in vocabs.translation, so writers
+// that only set Translation keep the list consistent.
+func encodeVocabDetails(v models.Vocab) encodedDetails {
+	t, _ := json.Marshal(nonNil(v.Tags))
+	e, _ := json.Marshal(nonNil(v.Examples))
+	grammar := "{}"
+	if v.Grammar != nil {
+		g, _ := json.Marshal(v.Grammar)
+		grammar = string(g)
+	}
+	alternates := []string{}
+	for _, tr := range v.Translations {
+		if !strings.EqualFold(tr, v.Translation) {
+			alternates = append(alternates, tr)
+		}
+	}
+	a, _ := json.Marshal(alternates)
+	syn, _ := json.Marshal(nonNil(v.Synonyms))
+	return encodedDetails{tags: string(t), examples: string(e), grammar: grammar, alternates: string(a), synonyms: string(syn)}
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
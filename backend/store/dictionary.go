@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"learnlang-backend/models"
+	"learnlang-backend/textnorm"
+)
+
+// DictionaryFilter selects dictionary entries by headword prefix.
+type DictionaryFilter struct {
+	LangID       string // required: language of the headwords
+	SourceLangID string // optional: language of the definitions
+	Prefix       string // folded with the language's rules before matching
+	Exact        bool   // match the whole folded headword instead of a prefix
+	Limit        int
+}
+
+// UpsertDictionaryEntries stores entries in one transaction. An entry with
+// the same language pair, folded headword and part of speech gains the new
+// definitions (existing ones keep their order) and, if given, the gender.
+func UpsertDictionaryEntries(entries []models.DictionaryEntry) (int, error) {
+	if db == nil || len(entries) == 0 {
+		return 0, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	codes := map[string]string{}
+	for _, e := range entries {
+		code, ok := codes[e.LangID]
+		if !ok {
+			_ = tx.QueryRowContext(ctx, `SELECT code FROM languages WHERE id=$1`, e.LangID).Scan(&code)
+			codes[e.LangID] = code
+		}
+		defs, _ := json.Marshal(nonNil(e.Definitions))
+		if _, err := tx.ExecContext(ctx, `INSERT INTO dictionary_entries (lang_id, source_lang_id, headword, headword_norm, part_of_speech, gender, definitions, source)
+			VALUES ($1, $2, $3, $4, $5, $6, $7::jsonb, $8)
+			ON CONFLICT (lang_id, source_lang_id, headword_norm, part_of_speech) DO UPDATE SET
+			  gender = COALESCE(NULLIF(EXCLUDED.gender, ''), dictionary_entries.gender),
+			  definitions = dictionary_entries.definitions || (
+			    SELECT COALESCE(jsonb_agg(d ORDER BY n), '[]') FROM jsonb_array_elements(EXCLUDED.definitions) WITH ORDINALITY AS x(d, n)
+			    WHERE NOT dictionary_entries.definitions @> jsonb_build_array(d))`,
+			e.LangID, e.SourceLangID, e.Headword, textnorm.Fold(code, e.Headword), e.PartOfSpeech, e.Gender, string(defs), e.Source); err != nil {
+			return 0, fmt.Errorf("entry %q: %w", e.Headword, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// SearchDictionary returns entries whose folded headword starts with the
+// folded prefix (or equals it, with Exact); exact matches come first, then
+// shorter headwords.
+func SearchDictionary(f DictionaryFilter) []models.DictionaryEntry {
+	if db == nil || f.LangID == "" || f.Prefix == "" {
+		return []models.DictionaryEntry{}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var code string
+	_ = db.QueryRowContext(ctx, `SELECT code FROM languages WHERE id=$1`, f.LangID).Scan(&code)
+	norm := textnorm.Fold(code, f.Prefix)
+	pattern := escapeLike(norm)
+	if !f.Exact {
+		pattern += "%"
+	}
+	rows, err := db.QueryContext(ctx, `SELECT id, lang_id, source_lang_id, headword, part_of_speech, gender, definitions::text, source
+		FROM dictionary_entries
+		WHERE lang_id = $1 AND ($2 = '' OR source_lang_id = $2) AND headword_norm LIKE $3
+		ORDER BY headword_norm = $4 DESC, length(headword_norm), headword_norm, part_of_speech
+		LIMIT $5`, f.LangID, f.SourceLangID, pattern, norm, f.Limit)
+	if err != nil {
+		return []models.DictionaryEntry{}
+	}
+	defer rows.Close()
+	out := []models.DictionaryEntry{}
+	for rows.Next() {
+		var e models.DictionaryEntry
+		var defs string
+		if err := rows.Scan(&e.ID, &e.LangID, &e.SourceLangID, &e.Headword, &e.PartOfSpeech, &e.Gender, &defs, &e.Source); err == nil {
+			_ = json.Unmarshal([]byte(defs), &e.Definitions)
+			out = append(out, e)
+		}
+	}
+	return out
+}
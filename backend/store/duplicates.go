@@ -0,0 +1,137 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"learnlang-backend/models"
+)
+
+// ListDuplicateVocabs groups a user's vocabs in one language whose folded
+// names (vocabs.name_norm) are equal ignoring diacritics. Only groups with 2+
+// members are returned. Rows not yet backfilled compare by their lowercased
+// name, so conflicts reported by BackfillNameNorm show up here for merging.
+func ListDuplicateVocabs(userID, langID string) []models.DuplicateGroup {
+	if db == nil || userID == "" || langID == "" {
+		return []models.DuplicateGroup{}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	rows, err := db.QueryContext(ctx, `SELECT d.key, d.id, d.image, d.name, d.translation, d.pack_id, d.pack_name, COALESCE(l.canonical_id, '')
+             FROM (
+               SELECT f_unaccent(COALESCE(NULLIF(v.name_norm, ''), lower(v.name))) AS key, v.id, v.image, v.name, v.translation, v.pack_id, p.name AS pack_name,
+                      count(*) OVER (PARTITION BY f_unaccent(COALESCE(NULLIF(v.name_norm, ''), lower(v.name)))) AS n
+               FROM vocabs v
+               JOIN packs p ON p.id = v.pack_id
+               WHERE p.user_id = $1 AND p.lang_id = $2
+             ) d
+             LEFT JOIN vocab_links l ON l.vocab_id = d.id
+             WHERE d.n > 1
+             ORDER BY d.key, d.pack_name, d.name`, userID, langID)
+	if err != nil {
+		return []models.DuplicateGroup{}
+	}
+	defer rows.Close()
+	out := []models.DuplicateGroup{}
+	for rows.Next() {
+		var key string
+		var d models.DuplicateVocab
+		if err := rows.Scan(&key, &d.ID, &d.Image, &d.Name, &d.Translation, &d.PackID, &d.PackName, &d.LinkedTo); err != nil {
+			continue
+		}
+		if len(out) == 0 || out[len(out)-1].Key != key {
+			out = append(out, models.DuplicateGroup{Key: key})
+		}
+		out[len(out)-1].Vocabs = append(out[len(out)-1].Vocabs, d)
+	}
+	return out
+}
+
+// MergeVocabs consolidates duplicates into keep: links pointing at the merged
+// vocabs are moved to keep, their images and audio clips are attached to keep,
+// the merged vocabs are deleted and keep is written with all its details
+// (callers fold the merged translations and names into them first).
+func MergeVocabs(keep models.Vocab, mergeIDs []string) error {
+	if db == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	in, args := inClause(mergeIDs, 2)
+	args = append([]any{keep.ID}, args...)
+	if _, err := tx.ExecContext(ctx, `DELETE FROM vocab_links WHERE vocab_id = $1 AND canonical_id IN (`+in+`)`, args...); err != nil {
+		return fmt.Errorf("drop links: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE vocab_links SET canonical_id = $1 WHERE canonical_id IN (`+in+`)`, args...); err != nil {
+		return fmt.Errorf("repoint links: %w", err)
+	}
+	// Keep's own clips stay first; merged clips follow in their original order.
+	if _, err := tx.ExecContext(ctx, `INSERT INTO vocab_audio (vocab_id, path, position)
+		SELECT $1, path, 1000000 + row_number() OVER (ORDER BY position, created_at) FROM vocab_audio WHERE vocab_id IN (`+in+`)
+		ON CONFLICT DO NOTHING`, args...); err != nil {
+		return fmt.Errorf("move audio: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO vocab_images (vocab_id, path, position)
+		SELECT $1, path, 1000000 + row_number() OVER (ORDER BY position, created_at) FROM vocab_images WHERE vocab_id IN (`+in+`)
+		ON CONFLICT DO NOTHING`, args...); err != nil {
+		return fmt.Errorf("move images: %w", err)
+	}
+	delIn, delArgs := inClause(mergeIDs, 1)
+	if _, err := tx.ExecContext(ctx, `DELETE FROM vocabs WHERE id IN (`+delIn+`)`, delArgs...); err != nil {
+		return fmt.Errorf("delete merged: %w", err)
+	}
+	if err := updateVocab(ctx, tx, keep); err != nil {
+		return fmt.Errorf("update kept: %w", err)
+	}
+	return tx.Commit()
+}
+
+// LinkVocabs marks linkIDs as duplicates of canonicalID without deleting them.
+// Existing links to any of linkIDs are re-pointed so links never chain.
+func LinkVocabs(canonicalID string, linkIDs []string) error {
+	if db == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM vocab_links WHERE vocab_id = $1`, canonicalID); err != nil {
+		return fmt.Errorf("unlink canonical: %w", err)
+	}
+	in, args := inClause(linkIDs, 2)
+	args = append([]any{canonicalID}, args...)
+	if _, err := tx.ExecContext(ctx, `UPDATE vocab_links SET canonical_id = $1 WHERE canonical_id IN (`+in+`)`, args...); err != nil {
+		return fmt.Errorf("repoint links: %w", err)
+	}
+	for _, id := range linkIDs {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO vocab_links (vocab_id, canonical_id) VALUES ($1, $2)
+                 ON CONFLICT (vocab_id) DO UPDATE SET canonical_id = EXCLUDED.canonical_id`, id, canonicalID); err != nil {
+			return fmt.Errorf("link %s: %w", id, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// inClause builds "$n,$n+1,..." placeholders for ids starting at position start.
+func inClause(ids []string, start int) (string, []any) {
+	placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		placeholders[i] = fmt.Sprintf("$%d", i+start)
+		args[i] = id
+	}
+	return strings.Join(placeholders, ","), args
+}
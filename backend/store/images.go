@@ -0,0 +1,48 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// VocabImages returns the ordered image paths for each of the given vocabs.
+// The primary image (vocabs.image) is always among them.
+func VocabImages(vocabIDs []string) map[string][]string {
+	return listVocabPaths("vocab_images", vocabIDs)
+}
+
+// AddVocabImages appends images to a vocab, after any existing ones.
+// Paths already attached are skipped.
+func AddVocabImages(vocabID string, paths []string) error {
+	return appendVocabPaths("vocab_images", vocabID, paths)
+}
+
+// SetVocabImages makes ordered the vocab's complete image list and primary
+// its primary image. Images left out are detached. The caller guarantees
+// ordered is non-empty, contains primary and only already attached paths.
+func SetVocabImages(vocabID string, ordered []string, primary string) error {
+	if db == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	in, args := inClause(ordered, 2)
+	if _, err := tx.ExecContext(ctx, `DELETE FROM vocab_images WHERE vocab_id=$1 AND path NOT IN (`+in+`)`, append([]any{vocabID}, args...)...); err != nil {
+		return fmt.Errorf("detach images: %w", err)
+	}
+	for i, p := range ordered {
+		if _, err := tx.ExecContext(ctx, `UPDATE vocab_images SET position=$1 WHERE vocab_id=$2 AND path=$3`, i, vocabID, p); err != nil {
+			return fmt.Errorf("reorder images: %w", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE vocabs SET image=$1 WHERE id=$2`, primary, vocabID); err != nil {
+		return fmt.Errorf("set primary: %w", err)
+	}
+	return tx.Commit()
+}
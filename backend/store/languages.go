@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"learnlang-backend/models"
+)
+
+// ErrLanguageNotFound is returned by UpdateLanguage for an unknown ID.
+var ErrLanguageNotFound = errors.New("language not found")
+
+const languageCols = `id, name, code, native_name, script, direction, collation, enabled`
+
+func languageDest(l *models.Language) []any {
+	return []any{&l.ID, &l.Name, &l.Code, &l.NativeName, &l.Script, &l.Direction, &l.Collation, &l.Enabled}
+}
+
+func listLanguages(where string) []models.Language {
+	if db == nil {
+		return []models.Language{}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rows, err := db.QueryContext(ctx, `SELECT `+languageCols+` FROM languages `+where+` ORDER BY name`)
+	if err != nil {
+		return []models.Language{}
+	}
+	defer rows.Close()
+	var out []models.Language
+	for rows.Next() {
+		var l models.Language
+		if err := rows.Scan(languageDest(&l)...); err == nil {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+func getLanguage(where string, arg any) (models.Language, bool) {
+	if db == nil {
+		return models.Language{}, false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var l models.Language
+	if err := db.QueryRowContext(ctx, `SELECT `+languageCols+` FROM languages WHERE `+where, arg).Scan(languageDest(&l)...); err != nil {
+		return models.Language{}, false
+	}
+	return l, true
+}
+
+// AllLanguages returns every language, including disabled ones.
+func AllLanguages() []models.Language {
+	return listLanguages(``)
+}
+
+// GetLanguageByID returns the language with the given ID, enabled or not.
+func GetLanguageByID(id string) (models.Language, bool) {
+	return getLanguage(`id=$1`, id)
+}
+
+// CreateLanguage stores a new language; a duplicate code violates the
+// unique constraint and is returned as an error.
+func CreateLanguage(l models.Language) error {
+	if db == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := db.ExecContext(ctx, `INSERT INTO languages (`+languageCols+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		l.ID, l.Name, l.Code, l.NativeName, l.Script, l.Direction, l.Collation, l.Enabled)
+	return err
+}
+
+// UpdateLanguage overwrites every column of an existing language.
+func UpdateLanguage(l models.Language) error {
+	if db == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	res, err := db.ExecContext(ctx, `UPDATE languages SET name=$1, code=$2, native_name=$3, script=$4, direction=$5, collation=$6, enabled=$7 WHERE id=$8`,
+		l.Name, l.Code, l.NativeName, l.Script, l.Direction, l.Collation, l.Enabled, l.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrLanguageNotFound
+	}
+	return nil
+}
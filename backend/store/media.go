@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"learnlang-backend/models"
+)
+
+// RecordMedia registers a stored blob. Re-recording the same hash marks it
+// as uploaded again, which restarts its garbage collection grace period.
+func RecordMedia(m models.Media) error {
+	if db == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := db.ExecContext(ctx, `INSERT INTO media (hash, path, content_type, size_bytes) VALUES ($1, $2, $3, $4) ON CONFLICT (hash) DO UPDATE SET last_uploaded_at = now()`, m.Hash, m.Path, m.ContentType, m.Size)
+	return err
+}
+
+// MediaRefCount returns how many vocab images, audio clips and cached
+// pronunciations reference the given public path (see the media_refs view).
+func MediaRefCount(path string) int {
+	if db == nil || path == "" {
+		return 0
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var n int
+	if err := db.QueryRowContext(ctx, `SELECT count(*) FROM media_refs WHERE path=$1`, path).Scan(&n); err != nil {
+		return 0
+	}
+	return n
+}
+
+// MediaLastUploaded returns when the blob at path was last uploaded, or the
+// zero time if it is not recorded.
+func MediaLastUploaded(path string) time.Time {
+	if db == nil || path == "" {
+		return time.Time{}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var t time.Time
+	if err := db.QueryRowContext(ctx, `SELECT last_uploaded_at FROM media WHERE path=$1`, path).Scan(&t); err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// ReferencedFiles returns the set of image and audio paths referenced by any
+// vocab or by the pronunciation cache (see the media_refs view).
+func ReferencedFiles() (map[string]bool, error) {
+	refs := map[string]bool{}
+	if db == nil {
+		return refs, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	rows, err := db.QueryContext(ctx, `SELECT DISTINCT path FROM media_refs`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p string
+		if err := rows.Scan(&p); err != nil {
+			return nil, err
+		}
+		refs[p] = true
+	}
+	return refs, rows.Err()
+}
+
+// DeleteMedia forgets a blob that has been removed from storage.
+func DeleteMedia(path string) error {
+	if db == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := db.ExecContext(ctx, `DELETE FROM media WHERE path=$1`, path)
+	return err
+}
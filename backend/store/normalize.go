@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"learnlang-backend/textnorm"
+)
+
+// packLangCode returns the language code of a pack, or "" if unknown.
+func packLangCode(ctx context.Context, packID string) string {
+	var code string
+	_ = db.QueryRowContext(ctx, `SELECT l.code FROM packs p JOIN languages l ON l.id = p.lang_id WHERE p.id=$1`, packID).Scan(&code)
+	return code
+}
+
+// nameNorm folds a vocab name with the rules of its pack's language, giving
+// the value stored in vocabs.name_norm.
+func nameNorm(ctx context.Context, packID, name string) string {
+	return textnorm.Fold(packLangCode(ctx, packID), name)
+}
+
+// NameNormBackfill reports what BackfillNameNorm did.
+type NameNormBackfill struct {
+	Updated int `json:"updated"`
+	// Conflicts lists vocabs left unfolded because another vocab in the same
+	// pack already has that folded name; merge or rename them and run again.
+	Conflicts []string `json:"conflicts"`
+}
+
+// BackfillNameNorm fills vocabs.name_norm for rows written before the column
+// existed. It is run once after upgrading (the backfill-names subcommand).
+func BackfillNameNorm() (NameNormBackfill, error) {
+	rep := NameNormBackfill{Conflicts: []string{}}
+	if db == nil {
+		return rep, nil
+	}
+	after := ""
+	for {
+		last, err := backfillNameNormBatch(after, 500, &rep)
+		if err != nil || last == "" {
+			return rep, err
+		}
+		after = last
+	}
+}
+
+// backfillNameNormBatch folds up to limit unfolded rows with IDs after the
+// given one and returns the last ID seen, or "" when none were left.
+func backfillNameNormBatch(after string, limit int, rep *NameNormBackfill) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	rows, err := db.QueryContext(ctx, `SELECT v.id, v.name, l.code FROM vocabs v
+		JOIN packs p ON p.id = v.pack_id
+		JOIN languages l ON l.id = p.lang_id
+		WHERE v.name_norm = '' AND v.name <> '' AND v.id > $1
+		ORDER BY v.id LIMIT $2`, after, limit)
+	if err != nil {
+		return "", err
+	}
+	type row struct{ id, norm string }
+	var todo []row
+	for rows.Next() {
+		var id, name, code string
+		if err := rows.Scan(&id, &name, &code); err != nil {
+			rows.Close()
+			return "", err
+		}
+		todo = append(todo, row{id, textnorm.Fold(code, name)})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	last := ""
+	for _, r := range todo {
+		last = r.id
+		if r.norm == "" {
+			continue // nothing to fold to
+		}
+		_, err := db.ExecContext(ctx, `UPDATE vocabs SET name_norm=$1 WHERE id=$2`, r.norm, r.id)
+		if IsUniqueViolation(err) {
+			rep.Conflicts = append(rep.Conflicts, r.id)
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		rep.Updated++
+	}
+	return last, nil
+}
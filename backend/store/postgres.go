@@ -11,7 +11,9 @@ import (
 	"time"
 
 	"learnlang-backend/models"
+	"learnlang-backend/textnorm"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
@@ -21,6 +23,13 @@ var (
 	probedSchema        bool
 )
 
+// IsUniqueViolation reports whether err is a Postgres unique_violation
+// (SQLSTATE 23505), e.g. a concurrent insert that won a check-then-insert race.
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
 // Init sets the global DB connection for the store package.
 func Init(d *sql.DB) {
 	db = d
@@ -103,26 +112,9 @@ func probeSchema(ctx context.Context) error {
 	return nil
 }
 
-// LanguagesList returns all supported languages.
+// LanguagesList returns the enabled languages, i.e. those new packs may use.
 func LanguagesList() []models.Language {
-	if db == nil {
-		return []models.Language{}
-	}
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	rows, err := db.QueryContext(ctx, `SELECT id, name, code FROM languages ORDER BY name`)
-	if err != nil {
-		return []models.Language{}
-	}
-	defer rows.Close()
-	var out []models.Language
-	for rows.Next() {
-		var l models.Language
-		if err := rows.Scan(&l.ID, &l.Name, &l.Code); err == nil {
-			out = append(out, l)
-		}
-	}
-	return out
+	return listLanguages(`WHERE enabled`)
 }
 
 // LanguageExists checks if a language code is supported.
@@ -138,28 +130,26 @@ func LanguageExists(code string) bool {
 }
 
 // GetLanguageByCode returns the language for the given code, if present.
+// Disabled languages are included.
 func GetLanguageByCode(code string) (models.Language, bool) {
-	if db == nil {
-		return models.Language{}, false
-	}
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
-	var l models.Language
-	err := db.QueryRowContext(ctx, `SELECT id, name, code FROM languages WHERE code=$1`, code).Scan(&l.ID, &l.Name, &l.Code)
-	if err != nil {
-		return models.Language{}, false
-	}
-	return l, true
+	return getLanguage(`code=$1`, code)
+}
+
+// PackFilter narrows ListPacks to a language pair; empty fields match any language.
+type PackFilter struct {
+	LangID       string // target language
+	SourceLangID string // language of the translations
 }
 
-// GetAllPacks returns all packs.
-func GetAllPacks() []models.Pack {
+// ListPacks returns all packs matching f.
+func ListPacks(f PackFilter) []models.Pack {
 	if db == nil {
 		return []models.Pack{}
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	rows, err := db.QueryContext(ctx, `SELECT id, name, lang_id, user_id, public FROM packs ORDER BY name`)
+	rows, err := db.QueryContext(ctx, `SELECT id, name, lang_id, source_lang_id, user_id, public FROM packs
+		WHERE ($1 = '' OR lang_id = $1) AND ($2 = '' OR source_lang_id = $2) ORDER BY name`, f.LangID, f.SourceLangID)
 	if err != nil {
 		return []models.Pack{}
 	}
@@ -167,20 +157,21 @@ func GetAllPacks() []models.Pack {
 	var out []models.Pack
 	for rows.Next() {
 		var p models.Pack
-		if err := rows.Scan(&p.ID, &p.Name, &p.LangID, &p.UserID, &p.Public); err == nil {
+		if err := rows.Scan(&p.ID, &p.Name, &p.LangID, &p.SourceLangID, &p.UserID, &p.Public); err == nil {
 			out = append(out, p)
 		}
 	}
 	return out
 }
 
-// parsePackKey parses a key of the form userID:langID:packName (all lowercased by caller).
-func parsePackKey(key string) (userID, langID, name string, err error) {
-	parts := strings.Split(key, ":")
-	if len(parts) != 3 {
-		return "", "", "", fmt.Errorf("invalid pack key")
-	}
-	return parts[0], parts[1], parts[2], nil
+// parsePackKey parses a key of the form userID:langID:sourceLangID:packName
+// (all lowercased by caller). The pack name may itself contain colons.
+func parsePackKey(key string) (userID, langID, sourceLangID, name string, err error) {
+	parts := strings.SplitN(key, ":", 4)
+	if len(parts) != 4 {
+		return "", "", "", "", fmt.Errorf("invalid pack key")
+	}
+	return parts[0], parts[1], parts[2], parts[3], nil
 }
 
 // PackExistsByKey reports whether the composite pack key already exists.
@@ -188,25 +179,32 @@ func PackExistsByKey(key string) bool {
 	if db == nil || key == "" {
 		return false
 	}
-	userID, langID, name, err := parsePackKey(key)
+	userID, langID, sourceLangID, name, err := parsePackKey(key)
 	if err != nil {
 		return false
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 	var one int
-	err = db.QueryRowContext(ctx, `SELECT 1 FROM packs WHERE lower(user_id)=lower($1) AND lower(lang_id)=lower($2) AND lower(name)=lower($3)`, userID, langID, name).Scan(&one)
+	err = db.QueryRowContext(ctx, `SELECT 1 FROM packs WHERE lower(user_id)=lower($1) AND lower(lang_id)=lower($2) AND lower(source_lang_id)=lower($3) AND lower(name)=lower($4)`,
+		userID, langID, sourceLangID, name).Scan(&one)
 	return err == nil
 }
 
-// CreatePack stores the pack.
+// DefaultSourceLangCode is the language of translations in packs created
+// without a source language.
+const DefaultSourceLangCode = "en"
+
+// CreatePack stores the pack. An empty SourceLangID means DefaultSourceLangCode.
 func CreatePack(p models.Pack, _ string) {
 	if db == nil {
 		return
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	_, _ = db.ExecContext(ctx, `INSERT INTO packs (id, name, lang_id, user_id, public) VALUES ($1, $2, $3, $4, $5)`, p.ID, p.Name, p.LangID, p.UserID, p.Public)
+	_, _ = db.ExecContext(ctx, `INSERT INTO packs (id, name, lang_id, source_lang_id, user_id, public)
+		VALUES ($1, $2, $3, COALESCE(NULLIF($4, ''), (SELECT id FROM languages WHERE code = $7)), $5, $6)`,
+		p.ID, p.Name, p.LangID, p.SourceLangID, p.UserID, p.Public, DefaultSourceLangCode)
 }
 
 // GetPackIDByKey returns the pack ID for the composite key if exists, else empty string.
@@ -214,30 +212,33 @@ func GetPackIDByKey(key string) string {
 	if db == nil || key == "" {
 		return ""
 	}
-	userID, langID, name, err := parsePackKey(key)
+	userID, langID, sourceLangID, name, err := parsePackKey(key)
 	if err != nil {
 		return ""
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 	var id string
-	err = db.QueryRowContext(ctx, `SELECT id FROM packs WHERE lower(user_id)=lower($1) AND lower(lang_id)=lower($2) AND lower(name)=lower($3)`, userID, langID, name).Scan(&id)
+	err = db.QueryRowContext(ctx, `SELECT id FROM packs WHERE lower(user_id)=lower($1) AND lower(lang_id)=lower($2) AND lower(source_lang_id)=lower($3) AND lower(name)=lower($4)`,
+		userID, langID, sourceLangID, name).Scan(&id)
 	if err != nil {
 		return ""
 	}
 	return id
 }
 
-// parseVocabKeyByPack parses a key packID:name (lowercased name by caller).
+// parseVocabKeyByPack parses a key packID:name (folded name by caller).
 func parseVocabKeyByPack(key string) (packID, name string, err error) {
-	parts := strings.Split(key, ":")
-	if len(parts) != 2 {
+	packID, name, ok := strings.Cut(key, ":")
+	if !ok {
 		return "", "", fmt.Errorf("invalid vocab key")
 	}
-	return parts[0], parts[1], nil
+	return packID, name, nil
 }
 
-// VocabExistsByKey reports whether the composite vocab key already exists.
+// VocabExistsByKey reports whether the composite vocab key already exists,
+// comparing names folded with the rules of the pack's language. Rows not yet
+// backfilled (empty name_norm) are folded here so they still count.
 func VocabExistsByKey(key string) bool {
 	if db == nil || key == "" {
 		return false
@@ -248,9 +249,24 @@ func VocabExistsByKey(key string) bool {
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
+	code := packLangCode(ctx, packID)
+	folded := textnorm.Fold(code, name)
 	var one int
-	err = db.QueryRowContext(ctx, `SELECT 1 FROM vocabs WHERE pack_id=$1 AND lower(name)=lower($2)`, packID, name).Scan(&one)
-	return err == nil
+	if err := db.QueryRowContext(ctx, `SELECT 1 FROM vocabs WHERE pack_id=$1 AND name_norm=$2`, packID, folded).Scan(&one); err == nil {
+		return true
+	}
+	rows, err := db.QueryContext(ctx, `SELECT name FROM vocabs WHERE pack_id=$1 AND name_norm = ''`, packID)
+	if err != nil {
+		return false
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var existing string
+		if rows.Scan(&existing) == nil && textnorm.Fold(code, existing) == folded {
+			return true
+		}
+	}
+	return false
 }
 
 // CreateVocab stores a vocab.
@@ -262,15 +278,19 @@ func CreateVocab(v models.Vocab, _ string) {
 	defer cancel()
 	_ = probeSchema(ctx)
 	if hasVocabTranslation {
-		_, _ = db.ExecContext(ctx, `INSERT INTO vocabs (id, image, name, translation, pack_id) VALUES ($1, $2, $3, $4, $5)`, v.ID, v.Image, v.Name, v.Translation, v.PackID)
+		d := encodeVocabDetails(v)
+		_, _ = db.ExecContext(ctx, `INSERT INTO vocabs (id, image, name, translation, pack_id, notes, part_of_speech, tags, examples, grammar, transliteration, alternate_translations, synonyms, name_norm, difficulty)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8::jsonb, $9::jsonb, $10::jsonb, $11, $12::jsonb, $13::jsonb, $14, $15)`,
+			v.ID, v.Image, v.Name, v.Translation, v.PackID, v.Notes, v.PartOfSpeech, d.tags, d.examples, d.grammar, v.Transliteration, d.alternates, d.synonyms, nameNorm(ctx, v.PackID, v.Name), v.Difficulty)
 		return
 	}
 	// fallback for older schema without translation column
 	_, _ = db.ExecContext(ctx, `INSERT INTO vocabs (id, image, name, pack_id) VALUES ($1, $2, $3, $4)`, v.ID, v.Image, v.Name, v.PackID)
 }
 
-// ListVocabs filters by user, lang and optional pack IDs.
-func ListVocabs(userID, langID string, packIDs []string) []models.Vocab {
+// ListVocabs filters by user, lang and optional source lang and pack IDs,
+// sorted by name in the language's collation.
+func ListVocabs(userID, langID, sourceLangID string, packIDs []string) []models.Vocab {
 	if db == nil {
 		return []models.Vocab{}
 	}
@@ -280,21 +300,24 @@ func ListVocabs(userID, langID string, packIDs []string) []models.Vocab {
 			return "COALESCE(v.translation, '') as translation, "
 		}
 		return "'' as translation, "
-	}() + `v.pack_id
+	}() + `v.pack_id, ` + vocabDetailColsOf("v.") + `
              FROM vocabs v
              JOIN packs p ON p.id = v.pack_id
              WHERE p.user_id = $1 AND p.lang_id = $2`
 	args := []any{userID, langID}
+	if sourceLangID != "" {
+		args = append(args, sourceLangID)
+		base += fmt.Sprintf(" AND p.source_lang_id = $%d", len(args))
+	}
 	if len(packIDs) > 0 {
 		// Build IN clause safely
 		placeholders := make([]string, len(packIDs))
 		for i := range packIDs {
-			placeholders[i] = fmt.Sprintf("$%d", i+3)
+			placeholders[i] = fmt.Sprintf("$%d", len(args)+1)
 			args = append(args, packIDs[i])
 		}
 		base += " AND v.pack_id IN (" + strings.Join(placeholders, ",") + ")"
 	}
-	base += " ORDER BY v.name"
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -306,15 +329,18 @@ func ListVocabs(userID, langID string, packIDs []string) []models.Vocab {
 	var out []models.Vocab
 	for rows.Next() {
 		var v models.Vocab
-		if err := rows.Scan(&v.ID, &v.Image, &v.Name, &v.Translation, &v.PackID); err == nil {
+		var d vocabDetails
+		if err := rows.Scan(append([]any{&v.ID, &v.Image, &v.Name, &v.Translation, &v.PackID}, d.dest(&v)...)...); err == nil {
+			d.apply(&v)
 			out = append(out, v)
 		}
 	}
+	sortVocabs(out, VocabSort{}, languageCollation(ctx, langID), "")
 	return out
 }
 
-// ListVocabsByPackID returns all vocabs for a single pack.
-func ListVocabsByPackID(packID string) []models.Vocab {
+// ListVocabsByPackID returns all vocabs for a single pack in the given order.
+func ListVocabsByPackID(packID string, order VocabSort) []models.Vocab {
 	if db == nil || packID == "" {
 		return []models.Vocab{}
 	}
@@ -327,7 +353,7 @@ func ListVocabsByPackID(packID string) []models.Vocab {
 	} else {
 		query += `'' as translation`
 	}
-	query += `, pack_id FROM vocabs WHERE pack_id=$1 ORDER BY name`
+	query += `, pack_id, ` + vocabDetailCols + ` FROM vocabs WHERE pack_id=$1`
 	rows, err := db.QueryContext(ctx, query, packID)
 	if err != nil {
 		return []models.Vocab{}
@@ -336,22 +362,28 @@ func ListVocabsByPackID(packID string) []models.Vocab {
 	var out []models.Vocab
 	for rows.Next() {
 		var v models.Vocab
-		if err := rows.Scan(&v.ID, &v.Image, &v.Name, &v.Translation, &v.PackID); err == nil {
+		var d vocabDetails
+		if err := rows.Scan(append([]any{&v.ID, &v.Image, &v.Name, &v.Translation, &v.PackID}, d.dest(&v)...)...); err == nil {
+			d.apply(&v)
 			out = append(out, v)
 		}
 	}
+	name, translation := packCollations(ctx, packID)
+	sortVocabs(out, order, name, translation)
 	return out
 }
 
-// Reset clears data tables (packs, vocabs). Useful for tests.
+// Reset clears data tables (users, packs, vocabs, media, tts_audio, translation_cache, dictionary_entries). Useful for tests.
 func Reset() {
 	if db == nil {
 		return
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	// Keep languages as-is (seeded by migrations)
-	_, _ = db.ExecContext(ctx, `TRUNCATE TABLE vocabs, packs RESTART IDENTITY CASCADE`)
+	// Keep the languages seeded by migrations (re-enabled); drop those added through the admin API.
+	_, _ = db.ExecContext(ctx, `TRUNCATE TABLE vocabs, packs, users, media, tts_audio, translation_cache, dictionary_entries RESTART IDENTITY CASCADE`)
+	_, _ = db.ExecContext(ctx, `DELETE FROM languages WHERE NOT seeded`)
+	_, _ = db.ExecContext(ctx, `UPDATE languages SET enabled = TRUE WHERE seeded`)
 }
 
 // GetPackByID returns a pack by ID if present.
@@ -362,7 +394,7 @@ func GetPackByID(id string) (models.Pack, bool) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 	var p models.Pack
-	err := db.QueryRowContext(ctx, `SELECT id, name, lang_id, user_id, public FROM packs WHERE id=$1`, id).Scan(&p.ID, &p.Name, &p.LangID, &p.UserID, &p.Public)
+	err := db.QueryRowContext(ctx, `SELECT id, name, lang_id, source_lang_id, user_id, public FROM packs WHERE id=$1`, id).Scan(&p.ID, &p.Name, &p.LangID, &p.SourceLangID, &p.UserID, &p.Public)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.Pack{}, false
@@ -386,27 +418,98 @@ func GetVocabByID(id string) (models.Vocab, bool) {
 	} else {
 		query += `'' as translation`
 	}
-	query += `, pack_id FROM vocabs WHERE id=$1`
+	query += `, pack_id, ` + vocabDetailCols + ` FROM vocabs WHERE id=$1`
 	var v models.Vocab
-	err := db.QueryRowContext(ctx, query, id).Scan(&v.ID, &v.Image, &v.Name, &v.Translation, &v.PackID)
+	var d vocabDetails
+	err := db.QueryRowContext(ctx, query, id).Scan(append([]any{&v.ID, &v.Image, &v.Name, &v.Translation, &v.PackID}, d.dest(&v)...)...)
 	if err != nil {
 		return models.Vocab{}, false
 	}
+	d.apply(&v)
 	return v, true
 }
 
-// UpdateVocab updates name, translation, and/or image of a vocab.
-func UpdateVocab(v models.Vocab) error {
+// UpdateVocabWithAudio updates v and, in the same transaction, detaches the
+// removeAudio clips and appends the addAudio ones.
+func UpdateVocabWithAudio(v models.Vocab, removeAudio, addAudio []string) error {
 	if db == nil {
 		return nil
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := updateVocab(ctx, tx, v); err != nil {
+		return err
+	}
+	if err := removeVocabPaths(ctx, tx, "vocab_audio", v.ID, removeAudio); err != nil {
+		return err
+	}
+	if err := appendVocabPathsTx(ctx, tx, "vocab_audio", v.ID, addAudio); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// updateVocab writes every column of v, including its details.
+func updateVocab(ctx context.Context, tx *sql.Tx, v models.Vocab) error {
 	_ = probeSchema(ctx)
 	if hasVocabTranslation {
-		_, err := db.ExecContext(ctx, `UPDATE vocabs SET image=$1, name=$2, translation=$3 WHERE id=$4`, v.Image, v.Name, v.Translation, v.ID)
+		d := encodeVocabDetails(v)
+		_, err := tx.ExecContext(ctx, `UPDATE vocabs SET image=$1, name=$2, translation=$3, notes=$4, part_of_speech=$5, tags=$6::jsonb, examples=$7::jsonb,
+			grammar=$8::jsonb, transliteration=$9, alternate_translations=$10::jsonb, synonyms=$11::jsonb, name_norm=$12, difficulty=$13 WHERE id=$14`,
+			v.Image, v.Name, v.Translation, v.Notes, v.PartOfSpeech, d.tags, d.examples, d.grammar, v.Transliteration, d.alternates, d.synonyms, nameNorm(ctx, v.PackID, v.Name), v.Difficulty, v.ID)
 		return err
 	}
-	_, err := db.ExecContext(ctx, `UPDATE vocabs SET image=$1, name=$2 WHERE id=$3`, v.Image, v.Name, v.ID)
+	_, err := tx.ExecContext(ctx, `UPDATE vocabs SET image=$1, name=$2 WHERE id=$3`, v.Image, v.Name, v.ID)
+	return err
+}
+
+// MoveVocab reassigns a vocab to another pack.
+func MoveVocab(id, packID string) error {
+	if db == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := db.ExecContext(ctx, `UPDATE vocabs SET pack_id=$1 WHERE id=$2`, packID, id)
+	return err
+}
+
+// InsertVocab stores a new vocab with all its details and attaches v.Images
+// (after the primary v.Image) and v.Audio in the same transaction, so a
+// failure never leaves a partial vocab.
+func InsertVocab(v models.Vocab) error {
+	if db == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := insertVocab(ctx, tx, v); err != nil {
+		return err
+	}
+	if err := appendVocabPathsTx(ctx, tx, "vocab_images", v.ID, v.Images); err != nil {
+		return err
+	}
+	if err := appendVocabPathsTx(ctx, tx, "vocab_audio", v.ID, v.Audio); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertVocab writes a new vocab row with its details.
+func insertVocab(ctx context.Context, tx *sql.Tx, v models.Vocab) error {
+	d := encodeVocabDetails(v)
+	_, err := tx.ExecContext(ctx, `INSERT INTO vocabs (id, image, name, translation, pack_id, notes, part_of_speech, tags, examples, grammar, transliteration, alternate_translations, synonyms, name_norm, difficulty)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8::jsonb, $9::jsonb, $10::jsonb, $11, $12::jsonb, $13::jsonb, $14, $15)`,
+		v.ID, v.Image, v.Name, v.Translation, v.PackID, v.Notes, v.PartOfSpeech, d.tags, d.examples, d.grammar, v.Transliteration, d.alternates, d.synonyms, nameNorm(ctx, v.PackID, v.Name), v.Difficulty)
 	return err
 }
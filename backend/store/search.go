@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"learnlang-backend/models"
+)
+
+// VocabSearchFilter scopes a vocab search.
+type VocabSearchFilter struct {
+	Query         string
+	UserID        string // packs owned by this user are always searched
+	LangID        string // optional language filter
+	IncludePublic bool   // also search other users' public packs
+	Tag           string // optional: only vocabs carrying this tag
+	PartOfSpeech  string // optional part-of-speech filter
+	Limit         int
+}
+
+// SearchVocabs matches name and translation using full-text and trigram
+// similarity on accent-stripped, lowercased text, plus fuzzy matches in
+// alternate translations and synonyms and full-text matches in notes, tags
+// and examples (both ranked lower). Results are ranked best first.
+func SearchVocabs(f VocabSearchFilter) []models.VocabSearchResult {
+	if db == nil || f.Query == "" {
+		return []models.VocabSearchResult{}
+	}
+	args := []any{f.Query, f.UserID, f.IncludePublic}
+	query := `WITH q AS (
+                SELECT f_unaccent(lower($1)) AS term,
+                       plainto_tsquery('simple', f_unaccent(lower($1))) AS tsq
+              )
+              SELECT v.id, v.image, v.name, v.translation, v.pack_id,
+                     v.notes, v.part_of_speech, v.tags::text, v.examples::text, v.grammar::text, v.transliteration,
+                     v.alternate_translations::text, v.synonyms::text, v.difficulty, v.created_at,
+                     p.name, p.user_id, p.public,
+                     (greatest(similarity(f_unaccent(lower(v.name)), q.term),
+                               similarity(f_unaccent(lower(v.translation)), q.term))
+                      + ts_rank(to_tsvector('simple', f_unaccent(v.name || ' ' || v.translation)), q.tsq)
+                      + CASE WHEN f_unaccent(lower(v.name)) = q.term OR f_unaccent(lower(v.translation)) = q.term THEN 1 ELSE 0 END
+                      + 0.5 * ts_rank(to_tsvector('simple', f_unaccent(lower(vocab_details_text(v.notes, v.tags, v.examples)))), q.tsq)
+                      + 0.8 * word_similarity(q.term, f_unaccent(lower(vocab_alternates_text(v.alternate_translations, v.synonyms))))
+                     )::float8 AS rank
+              FROM vocabs v
+              JOIN packs p ON p.id = v.pack_id
+              CROSS JOIN q
+              WHERE (p.user_id = $2 OR ($3 AND p.public))
+                AND (to_tsvector('simple', f_unaccent(v.name || ' ' || v.translation)) @@ q.tsq
+                     OR f_unaccent(lower(v.name)) % q.term
+                     OR f_unaccent(lower(v.translation)) % q.term
+                     OR strpos(f_unaccent(lower(v.name)), q.term) > 0
+                     OR strpos(f_unaccent(lower(v.translation)), q.term) > 0
+                     OR to_tsvector('simple', f_unaccent(lower(vocab_details_text(v.notes, v.tags, v.examples)))) @@ q.tsq
+                     OR q.term <% f_unaccent(lower(vocab_alternates_text(v.alternate_translations, v.synonyms))))`
+	if f.LangID != "" {
+		args = append(args, f.LangID)
+		query += fmt.Sprintf(" AND p.lang_id = $%d", len(args))
+	}
+	if f.Tag != "" {
+		args = append(args, f.Tag)
+		query += fmt.Sprintf(" AND v.tags ? $%d", len(args))
+	}
+	if f.PartOfSpeech != "" {
+		args = append(args, f.PartOfSpeech)
+		query += fmt.Sprintf(" AND v.part_of_speech = $%d", len(args))
+	}
+	query += " ORDER BY rank DESC, v.name"
+	if f.Limit > 0 {
+		args = append(args, f.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return []models.VocabSearchResult{}
+	}
+	defer rows.Close()
+	out := []models.VocabSearchResult{}
+	for rows.Next() {
+		var s models.VocabSearchResult
+		var d vocabDetails
+		dest := append([]any{&s.ID, &s.Image, &s.Name, &s.Translation, &s.PackID}, d.dest(&s.Vocab)...)
+		if err := rows.Scan(append(dest, &s.PackName, &s.PackUserID, &s.PackPublic, &s.Rank)...); err == nil {
+			d.apply(&s.Vocab)
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+
+	"learnlang-backend/models"
+)
+
+// Sort keys for vocab lists.
+const (
+	VocabSortName        = "name"        // pack language collation
+	VocabSortTranslation = "translation" // source language collation
+	VocabSortCreated     = "created"     // oldest first
+	VocabSortDifficulty  = "difficulty"  // easiest first; unrated always last
+)
+
+// VocabSort orders ListVocabsByPackID. The zero value sorts by name.
+type VocabSort struct {
+	Key  string
+	Desc bool
+}
+
+// packCollations returns the collation locales of a pack's language and of
+// its translations' language.
+func packCollations(ctx context.Context, packID string) (name, translation string) {
+	_ = db.QueryRowContext(ctx, `SELECT COALESCE(NULLIF(l.collation, ''), l.code), COALESCE(NULLIF(s.collation, ''), s.code)
+		FROM packs p JOIN languages l ON l.id = p.lang_id JOIN languages s ON s.id = p.source_lang_id
+		WHERE p.id = $1`, packID).Scan(&name, &translation)
+	return name, translation
+}
+
+// languageCollation returns the collation locale of a language.
+func languageCollation(ctx context.Context, langID string) string {
+	var locale string
+	_ = db.QueryRowContext(ctx, `SELECT COALESCE(NULLIF(collation, ''), code) FROM languages WHERE id = $1`, langID).Scan(&locale)
+	return locale
+}
+
+// sortVocabs orders vs in place. Names and translations are compared with
+// the collation rules of their locale (so ä sorts with a in German and
+// Devanagari follows the Hindi alphabet), not byte order; ties fall back to
+// name, then ID, so the order is the same on every call.
+func sortVocabs(vs []models.Vocab, s VocabSort, nameLocale, translationLocale string) {
+	names := collationKeys(vs, nameLocale, func(v models.Vocab) string { return v.Name })
+	var translations [][]byte
+	if s.Key == VocabSortTranslation {
+		translations = collationKeys(vs, translationLocale, func(v models.Vocab) string { return v.Translation })
+	}
+	idx := make([]int, len(vs))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		a, b := idx[i], idx[j]
+		c := 0
+		switch s.Key {
+		case VocabSortTranslation:
+			c = bytes.Compare(translations[a], translations[b])
+		case VocabSortCreated:
+			c = vs[a].CreatedAt.Compare(vs[b].CreatedAt)
+		case VocabSortDifficulty:
+			da, dn := vs[a].Difficulty, vs[b].Difficulty
+			if (da == 0) != (dn == 0) {
+				return dn == 0 // unrated last in either direction
+			}
+			c = da - dn
+		}
+		if c == 0 {
+			c = bytes.Compare(names[a], names[b])
+		}
+		if c == 0 {
+			return vs[a].ID < vs[b].ID
+		}
+		if s.Desc {
+			return c > 0
+		}
+		return c < 0
+	})
+	sorted := make([]models.Vocab, len(vs))
+	for i, k := range idx {
+		sorted[i] = vs[k]
+	}
+	copy(vs, sorted)
+}
+
+// collationKeys computes sort keys of field(v) for each vocab under locale.
+// An unknown or empty locale falls back to the root collation.
+func collationKeys(vs []models.Vocab, locale string, field func(models.Vocab) string) [][]byte {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		tag = language.Und
+	}
+	c := collate.New(tag)
+	var buf collate.Buffer
+	keys := make([][]byte, len(vs))
+	for i, v := range vs {
+		keys[i] = bytes.Clone(c.KeyFromString(&buf, field(v)))
+		buf.Reset()
+	}
+	return keys
+}
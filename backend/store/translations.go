@@ -0,0 +1,49 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// GetTranslationCache returns suggestions cached for text in a language pair
+// by the named translator, ignoring entries older than maxAge (0 keeps them
+// forever).
+func GetTranslationCache(provider, fromCode, toCode, text string, maxAge time.Duration) ([]string, bool) {
+	if db == nil {
+		return nil, false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var raw string
+	var created time.Time
+	err := db.QueryRowContext(ctx, `SELECT suggestions::text, created_at FROM translation_cache WHERE provider=$1 AND from_code=$2 AND to_code=$3 AND text=$4`,
+		provider, fromCode, toCode, text).Scan(&raw, &created)
+	if err != nil || (maxAge > 0 && time.Since(created) > maxAge) {
+		return nil, false
+	}
+	var out []string
+	if json.Unmarshal([]byte(raw), &out) != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+// SaveTranslationCache stores suggestions for text, replacing an existing
+// entry, and drops entries older than maxAge (0 keeps them forever).
+func SaveTranslationCache(provider, fromCode, toCode, text string, suggestions []string, maxAge time.Duration) error {
+	if db == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	b, _ := json.Marshal(nonNil(suggestions))
+	_, err := db.ExecContext(ctx, `INSERT INTO translation_cache (provider, from_code, to_code, text, suggestions) VALUES ($1, $2, $3, $4, $5::jsonb)
+		ON CONFLICT (provider, from_code, to_code, text) DO UPDATE SET suggestions = EXCLUDED.suggestions, created_at = now()`,
+		provider, fromCode, toCode, text, string(b))
+	if err != nil || maxAge <= 0 {
+		return err
+	}
+	_, err = db.ExecContext(ctx, `DELETE FROM translation_cache WHERE created_at < now() - make_interval(secs => $1)`, maxAge.Seconds())
+	return err
+}
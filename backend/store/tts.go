@@ -0,0 +1,133 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// VocabSpeech identifies the text and language a vocab's pronunciation is synthesized from.
+type VocabSpeech struct {
+	VocabID  string
+	Text     string
+	LangCode string
+}
+
+// GetTTSAudio returns the cached pronunciation path for text in a language.
+func GetTTSAudio(langCode, text string) (string, bool) {
+	if db == nil {
+		return "", false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var p string
+	err := db.QueryRowContext(ctx, `SELECT path FROM tts_audio WHERE lang_code=$1 AND text=$2`, langCode, text).Scan(&p)
+	if err != nil {
+		return "", false
+	}
+	return p, true
+}
+
+// SaveTTSAudio caches a synthesized pronunciation; an existing entry wins.
+func SaveTTSAudio(langCode, text, path string) error {
+	if db == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := db.ExecContext(ctx, `INSERT INTO tts_audio (lang_code, text, path) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`, langCode, text, path)
+	return err
+}
+
+// PruneTTSAudio forgets cached pronunciations whose text no vocab in that
+// language uses any more (after a rename or delete) and returns how many were
+// removed. Their blobs are then left to the garbage collector.
+func PruneTTSAudio() (int64, error) {
+	if db == nil {
+		return 0, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := db.ExecContext(ctx, `DELETE FROM tts_audio t WHERE NOT EXISTS (
+		SELECT 1 FROM vocabs v
+		JOIN packs p ON p.id = v.pack_id
+		JOIN languages l ON l.id = p.lang_id
+		WHERE l.code = t.lang_code AND v.name = t.text)`)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// GetVocabSpeech returns the pronunciation source of a vocab: its name and the pack's language code.
+func GetVocabSpeech(vocabID string) (VocabSpeech, bool) {
+	if db == nil {
+		return VocabSpeech{}, false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	s := VocabSpeech{VocabID: vocabID}
+	err := db.QueryRowContext(ctx, `SELECT v.name, l.code FROM vocabs v
+		JOIN packs p ON p.id = v.pack_id
+		JOIN languages l ON l.id = p.lang_id
+		WHERE v.id=$1`, vocabID).Scan(&s.Text, &s.LangCode)
+	if err != nil {
+		return VocabSpeech{}, false
+	}
+	return s, true
+}
+
+// VocabsMissingSpeech lists vocabs with neither recorded nor cached synthesized audio.
+func VocabsMissingSpeech(limit int) ([]VocabSpeech, error) {
+	if db == nil {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	rows, err := db.QueryContext(ctx, `SELECT v.id, v.name, l.code FROM vocabs v
+		JOIN packs p ON p.id = v.pack_id
+		JOIN languages l ON l.id = p.lang_id
+		WHERE NOT EXISTS (SELECT 1 FROM vocab_audio a WHERE a.vocab_id = v.id)
+		  AND NOT EXISTS (SELECT 1 FROM tts_audio t WHERE t.lang_code = l.code AND t.text = v.name)
+		ORDER BY random() -- entries that keep failing must not starve the rest
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []VocabSpeech
+	for rows.Next() {
+		var s VocabSpeech
+		if err := rows.Scan(&s.VocabID, &s.Text, &s.LangCode); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// VocabTTSAudio returns cached synthesized pronunciation paths by vocab ID.
+func VocabTTSAudio(vocabIDs []string) map[string]string {
+	out := map[string]string{}
+	if db == nil || len(vocabIDs) == 0 {
+		return out
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	in, args := inClause(vocabIDs, 1)
+	rows, err := db.QueryContext(ctx, `SELECT v.id, t.path FROM vocabs v
+		JOIN packs p ON p.id = v.pack_id
+		JOIN languages l ON l.id = p.lang_id
+		JOIN tts_audio t ON t.lang_code = l.code AND t.text = v.name
+		WHERE v.id IN (`+in+`)`, args...)
+	if err != nil {
+		return out
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, p string
+		if err := rows.Scan(&id, &p); err == nil {
+			out[id] = p
+		}
+	}
+	return out
+}
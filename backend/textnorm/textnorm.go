@@ -0,0 +1,55 @@
+// Package textnorm folds text for equality checks: Unicode NFC, full case
+// folding and per-language rules keyed on languages.code. Folded text is what
+// vocab uniqueness, duplicate detection and name lookups compare.
+package textnorm
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// rule is an extra folding step applied after case folding.
+type rule func(string) string
+
+// rules holds the language-specific steps. Each must be idempotent and
+// commute with the generic steps, so Fold(lang, Fold("", s)) == Fold(lang, s).
+var rules = map[string][]rule{
+	// ß and ẞ already fold to "ss" under full case folding.
+	"de": nil,
+	// Nukta marks foreign sounds (ज़ z vs ज j) and is often omitted in writing.
+	// NFC decomposes the precomposed nukta letters, so dropping U+093C covers both.
+	"hi": {dropRunes('़')},
+}
+
+var folder = cases.Fold()
+
+// Fold returns the comparison form of s in the language langCode ("" for
+// language-independent folding): NFC, case-folded, language rules applied and
+// whitespace collapsed.
+func Fold(langCode, s string) string {
+	s = norm.NFC.String(folder.String(norm.NFC.String(s)))
+	for _, r := range rules[strings.ToLower(langCode)] {
+		s = r(s)
+	}
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Equal reports whether a and b fold to the same text in langCode.
+func Equal(langCode, a, b string) bool {
+	return Fold(langCode, a) == Fold(langCode, b)
+}
+
+func dropRunes(drop ...rune) rule {
+	return func(s string) string {
+		return strings.Map(func(r rune) rune {
+			for _, d := range drop {
+				if r == d {
+					return -1
+				}
+			}
+			return r
+		}, s)
+	}
+}
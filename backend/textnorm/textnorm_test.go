@@ -0,0 +1,35 @@
+package textnorm
+
+import "testing"
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		lang, a, b string
+		equal      bool
+	}{
+		{"de", "Müller", "Müller", true}, // NFC vs NFD
+		{"de", "Straße", "STRASSE", true},
+		{"de", "groß", "gross", true},
+		{"de", "Müller", "Muller", false}, // diacritics are significant
+		{"hi", "\u095Bरूर", "जरूर", true}, // precomposed nukta letter vs none
+		{"hi", "ज़रूर", "जरूर", true},     // combining nukta vs none
+		{"", "ज़रूर", "जरूर", false},      // nukta only folds for Hindi
+		{"hi", "  चाकू ", "चाकू", true},
+		{"de", "guten  Tag", "Guten Tag", true},
+	}
+	for _, tt := range tests {
+		if got := Equal(tt.lang, tt.a, tt.b); got != tt.equal {
+			t.Errorf("Equal(%q, %q, %q) = %v; want %v (folded %q vs %q)", tt.lang, tt.a, tt.b, got, tt.equal, Fold(tt.lang, tt.a), Fold(tt.lang, tt.b))
+		}
+	}
+}
+
+func TestFold_Idempotent(t *testing.T) {
+	for _, s := range []string{"Straße", "ज़रूर", "MÜLLER"} {
+		for _, lang := range []string{"", "de", "hi"} {
+			if once := Fold(lang, s); Fold(lang, Fold("", s)) != once || Fold(lang, once) != once {
+				t.Errorf("Fold(%q, %q) not idempotent", lang, s)
+			}
+		}
+	}
+}
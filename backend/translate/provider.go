@@ -0,0 +1,226 @@
+package translate
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"learnlang-backend/store"
+	"learnlang-backend/textnorm"
+)
+
+// LibreTranslate calls a self-hosted LibreTranslate-compatible server
+// (POST {URL}/translate).
+type LibreTranslate struct {
+	URL          string // base URL, e.g. http://localhost:5000
+	APIKey       string // optional
+	Alternatives int    // extra suggestions to ask for
+	Client       *http.Client
+}
+
+// NewLibreTranslateFromEnv builds a LibreTranslate client from
+// LIBRETRANSLATE_URL and the optional LIBRETRANSLATE_API_KEY.
+func NewLibreTranslateFromEnv() (*LibreTranslate, error) {
+	u := strings.TrimRight(strings.TrimSpace(os.Getenv("LIBRETRANSLATE_URL")), "/")
+	if u == "" {
+		return nil, errors.New("LIBRETRANSLATE_URL must be set for TRANSLATE_PROVIDER=libretranslate")
+	}
+	return &LibreTranslate{URL: u, APIKey: os.Getenv("LIBRETRANSLATE_API_KEY"), Alternatives: 2, Client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// Name implements Translator; servers at different URLs are cached apart.
+func (l *LibreTranslate) Name() string {
+	return "libretranslate " + l.URL
+}
+
+// Translate implements Translator.
+func (l *LibreTranslate) Translate(ctx context.Context, text, from, to string) ([]string, error) {
+	body, _ := json.Marshal(map[string]any{
+		"q": text, "source": from, "target": to, "format": "text",
+		"alternatives": l.Alternatives, "api_key": l.APIKey,
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.URL+"/translate", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := l.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	var out struct {
+		TranslatedText string   `json:"translatedText"`
+		Alternatives   []string `json:"alternatives"`
+		Error          string   `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&out); err != nil && res.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("libretranslate: invalid response: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		if out.Error == "" {
+			out.Error = res.Status
+		}
+		return nil, fmt.Errorf("libretranslate: %s", out.Error)
+	}
+	if out.TranslatedText == "" {
+		return nil, nil
+	}
+	return append([]string{out.TranslatedText}, out.Alternatives...), nil
+}
+
+// Dictionary looks translations up in a bilingual word list loaded into
+// memory. Lookups ignore case and the folding rules of the source language.
+type Dictionary struct {
+	entries map[string][]string // from|to|folded text -> translations
+}
+
+// NewDictionaryFromEnv loads the word list at TRANSLATE_DICTIONARY.
+func NewDictionaryFromEnv() (*Dictionary, error) {
+	path := os.Getenv("TRANSLATE_DICTIONARY")
+	if path == "" {
+		return nil, errors.New("TRANSLATE_DICTIONARY must be set for TRANSLATE_PROVIDER=dictionary")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("dictionary: %w", err)
+	}
+	defer f.Close()
+	return ParseDictionary(f)
+}
+
+// ParseDictionary reads a tab-separated word list with one entry per line:
+//
+//	from<TAB>to<TAB>text<TAB>translation[<TAB>translation...]
+//
+// where from and to are language codes. Repeated entries add translations;
+// blank lines and lines starting with # are ignored.
+func ParseDictionary(r io.Reader) (*Dictionary, error) {
+	d := &Dictionary{entries: map[string][]string{}}
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 4 {
+			return nil, fmt.Errorf("dictionary line %d: expected from, to, text and a translation", n)
+		}
+		k := d.key(fields[2], fields[0], fields[1])
+		for _, tr := range fields[3:] {
+			if tr = strings.TrimSpace(tr); tr != "" {
+				d.entries[k] = append(d.entries[k], tr)
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("dictionary: %w", err)
+	}
+	return d, nil
+}
+
+func (d *Dictionary) key(text, from, to string) string {
+	from, to = strings.ToLower(strings.TrimSpace(from)), strings.ToLower(strings.TrimSpace(to))
+	return from + "|" + to + "|" + textnorm.Fold(from, text)
+}
+
+// Name implements Translator.
+func (d *Dictionary) Name() string {
+	return "dictionary"
+}
+
+// Cacheable reports false: lookups are in memory, and caching them would keep
+// serving entries removed from the file.
+func (d *Dictionary) Cacheable() bool {
+	return false
+}
+
+// Translate implements Translator.
+func (d *Dictionary) Translate(_ context.Context, text, from, to string) ([]string, error) {
+	return d.entries[d.key(text, from, to)], nil
+}
+
+// StoredDictionary looks translations up in the dictionary imported into
+// Postgres (store.SearchDictionary, see the dict-import command): the
+// definitions of entries whose folded headword in the from language equals
+// the text, with definitions in the to language.
+type StoredDictionary struct{}
+
+// storedDictionaryEntries caps the entries (one per part of speech) read per lookup.
+const storedDictionaryEntries = 10
+
+// Name implements Translator.
+func (StoredDictionary) Name() string {
+	return "stored-dictionary"
+}
+
+// Cacheable reports false: the lookup is a local query, and caching it would
+// hide later imports.
+func (StoredDictionary) Cacheable() bool {
+	return false
+}
+
+// Translate implements Translator.
+func (StoredDictionary) Translate(_ context.Context, text, from, to string) ([]string, error) {
+	fromLang, ok := store.GetLanguageByCode(from)
+	if !ok {
+		return nil, nil
+	}
+	toLang, ok := store.GetLanguageByCode(to)
+	if !ok {
+		return nil, nil
+	}
+	var out []string
+	for _, e := range store.SearchDictionary(store.DictionaryFilter{LangID: fromLang.ID, SourceLangID: toLang.ID, Prefix: text, Exact: true, Limit: storedDictionaryEntries}) {
+		out = append(out, e.Definitions...)
+	}
+	return out, nil
+}
+
+// Fake suggests "<to>:<text>" and records what it was asked for.
+type Fake struct {
+	mu    sync.Mutex
+	ID    string   // Name, "fake" when empty
+	Calls []string // "from:to:text"
+	Err   error    // returned instead of suggestions when set
+}
+
+// Name implements Translator.
+func (f *Fake) Name() string {
+	if f.ID == "" {
+		return "fake"
+	}
+	return f.ID
+}
+
+// Translate implements Translator.
+func (f *Fake) Translate(_ context.Context, text, from, to string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Calls = append(f.Calls, from+":"+to+":"+text)
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return []string{to + ":" + text}, nil
+}
+
+// CallCount reports how many times Translate ran.
+func (f *Fake) CallCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.Calls)
+}
@@ -0,0 +1,125 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLibreTranslate_Translate(t *testing.T) {
+	// Stand-in server speaking the LibreTranslate /translate protocol.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Q      string `json:"q"`
+			Source string `json:"source"`
+			Target string `json:"target"`
+			APIKey string `json:"api_key"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		switch {
+		case r.URL.Path != "/translate" || req.APIKey != "key":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":"Invalid API key"}`))
+		case req.Source == "de" && req.Target == "hi" && req.Q == "Messer":
+			_, _ = w.Write([]byte(`{"translatedText":"चाकू","alternatives":["छुरी"]}`))
+		default:
+			_, _ = w.Write([]byte(`{"translatedText":""}`))
+		}
+	}))
+	defer srv.Close()
+
+	l := &LibreTranslate{URL: srv.URL, APIKey: "key", Alternatives: 2, Client: srv.Client()}
+	got, err := l.Translate(context.Background(), "Messer", "de", "hi")
+	if err != nil || strings.Join(got, ",") != "चाकू,छुरी" {
+		t.Fatalf("got %v, %v", got, err)
+	}
+	if got, err := l.Translate(context.Background(), "Gabel", "de", "hi"); err != nil || len(got) != 0 {
+		t.Fatalf("expected no suggestions, got %v, %v", got, err)
+	}
+	l.APIKey = "wrong"
+	if _, err := l.Translate(context.Background(), "Messer", "de", "hi"); err == nil || !strings.Contains(err.Error(), "Invalid API key") {
+		t.Fatalf("expected server error, got %v", err)
+	}
+}
+
+func TestDictionary(t *testing.T) {
+	d, err := ParseDictionary(strings.NewReader("# from\tto\ttext\ttranslations\nde\thi\tMesser\tचाकू\nde\thi\tmesser\tछुरी\n\nde\ten\tStraße\tstreet\n"))
+	if err != nil {
+		t.Fatalf("ParseDictionary: %v", err)
+	}
+	if got, _ := d.Translate(context.Background(), "MESSER", "de", "hi"); strings.Join(got, ",") != "चाकू,छुरी" {
+		t.Errorf("got %v", got)
+	}
+	if got, _ := d.Translate(context.Background(), "strasse", "de", "en"); len(got) != 1 || got[0] != "street" {
+		t.Errorf("folded lookup: got %v", got)
+	}
+	if got, _ := d.Translate(context.Background(), "Messer", "de", "en"); len(got) != 0 {
+		t.Errorf("wrong pair: got %v", got)
+	}
+	if _, err := ParseDictionary(strings.NewReader("de\thi\tMesser\n")); err == nil {
+		t.Error("expected error for a line without translation")
+	}
+	if cacheable(d) || cacheable(StoredDictionary{}) || !cacheable(&Fake{}) {
+		t.Error("expected only the dictionaries to skip the cache")
+	}
+}
+
+func TestChain(t *testing.T) {
+	d, _ := ParseDictionary(strings.NewReader("de\thi\tMesser\tचाकू\n"))
+	failing := &Fake{Err: errors.New("down")}
+	c := Chain{failing, d, &Fake{ID: "backup"}}
+	if got, err := c.Translate(context.Background(), "Messer", "de", "hi"); err != nil || got[0] != "चाकू" {
+		t.Fatalf("got %v, %v", got, err)
+	}
+	if got, err := c.Translate(context.Background(), "Gabel", "de", "hi"); err != nil || got[0] != "hi:Gabel" {
+		t.Fatalf("expected fallback to the last translator, got %v, %v", got, err)
+	}
+	if _, err := (Chain{failing}).Translate(context.Background(), "Gabel", "de", "hi"); err == nil {
+		t.Fatal("expected error when every translator fails")
+	}
+	if got := c.Name(); got != "fake,dictionary,backup" {
+		t.Errorf("Name() = %q", got)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("TRANSLATE_PROVIDER", "")
+	if tr, err := FromEnv(); tr != nil || err != nil {
+		t.Fatalf("expected disabled, got %v %v", tr, err)
+	}
+	t.Setenv("TRANSLATE_PROVIDER", "libretranslate")
+	t.Setenv("LIBRETRANSLATE_URL", "")
+	if _, err := FromEnv(); err == nil {
+		t.Fatal("expected error without LIBRETRANSLATE_URL")
+	}
+	t.Setenv("LIBRETRANSLATE_URL", "http://localhost:5000/")
+	t.Setenv("TRANSLATE_PROVIDER", "libretranslate, fake")
+	tr, err := FromEnv()
+	if c, ok := tr.(Chain); err != nil || !ok || len(c) != 2 || c[0].(*LibreTranslate).URL != "http://localhost:5000" {
+		t.Fatalf("expected chain, got %#v %v", tr, err)
+	}
+	t.Setenv("TRANSLATE_PROVIDER", "dictionary")
+	t.Setenv("TRANSLATE_DICTIONARY", "")
+	if _, err := FromEnv(); err == nil {
+		t.Fatal("expected error without TRANSLATE_DICTIONARY")
+	}
+	t.Setenv("TRANSLATE_PROVIDER", "stored-dictionary")
+	if tr, err := FromEnv(); err != nil || tr != (StoredDictionary{}) {
+		t.Fatalf("expected stored dictionary, got %#v %v", tr, err)
+	}
+	t.Setenv("TRANSLATE_PROVIDER", "google")
+	if _, err := FromEnv(); err == nil {
+		t.Fatal("expected error for unknown provider")
+	}
+}
+
+func TestCleanSuggestions(t *testing.T) {
+	got := cleanSuggestions([]string{" चाकू ", "", "चाकू", "a", "A", "b", "c", "d", "e"})
+	if strings.Join(got, ",") != "चाकू,a,b,c,d" {
+		t.Fatalf("got %v", got)
+	}
+}
@@ -0,0 +1,227 @@
+// Package translate suggests translations for words with a pluggable
+// machine-translation backend and caches the results in Postgres.
+package translate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"learnlang-backend/store"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrDisabled is returned when no translator is configured.
+var ErrDisabled = errors.New("translation suggestions are not configured")
+
+// Translator suggests translations of text from one language to another
+// (ISO codes as stored in languages.code), best first. No suggestions is not
+// an error. Name identifies the backend in cache keys, so suggestions from
+// different translators are never served for each other.
+type Translator interface {
+	Translate(ctx context.Context, text, from, to string) ([]string, error)
+	Name() string
+}
+
+// MaxSuggestions caps the suggestions returned for one text.
+const MaxSuggestions = 5
+
+var (
+	defaultMu         sync.Mutex
+	defaultSet        bool
+	defaultTranslator Translator
+)
+
+// Default returns the translator configured by FromEnv, or nil if
+// suggestions are disabled. Invalid configuration is logged once and treated
+// as disabled.
+func Default() Translator {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if !defaultSet {
+		t, err := FromEnv()
+		if err != nil {
+			log.Printf("translation suggestions disabled: %v", err)
+		}
+		defaultTranslator, defaultSet = t, true
+	}
+	return defaultTranslator
+}
+
+// SetDefault overrides the process-wide translator (tests, custom wiring).
+// Passing nil disables suggestions.
+func SetDefault(t Translator) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultTranslator, defaultSet = t, true
+}
+
+// FromEnv builds a translator from TRANSLATE_PROVIDER, a comma-separated list
+// of "libretranslate" (see NewLibreTranslateFromEnv), "dictionary" (see
+// NewDictionaryFromEnv), "stored-dictionary" (see StoredDictionary) and
+// "fake", tried in order until one has suggestions. Empty disables suggestions.
+func FromEnv() (Translator, error) {
+	var chain Chain
+	for _, name := range strings.Split(os.Getenv("TRANSLATE_PROVIDER"), ",") {
+		var (
+			t   Translator
+			err error
+		)
+		switch name = strings.ToLower(strings.TrimSpace(name)); name {
+		case "":
+			continue
+		case "libretranslate":
+			t, err = NewLibreTranslateFromEnv()
+		case "dictionary":
+			t, err = NewDictionaryFromEnv()
+		case "stored-dictionary":
+			t = StoredDictionary{}
+		case "fake":
+			t = &Fake{}
+		default:
+			err = fmt.Errorf("unknown TRANSLATE_PROVIDER %q", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, t)
+	}
+	switch len(chain) {
+	case 0:
+		return nil, nil
+	case 1:
+		return chain[0], nil
+	}
+	return chain, nil
+}
+
+// Chain asks each translator in turn and returns the first non-empty
+// suggestions. A failing translator is skipped unless all of them fail.
+type Chain []Translator
+
+// Name implements Translator: the names of the chained translators in order.
+func (c Chain) Name() string {
+	names := make([]string, len(c))
+	for i, t := range c {
+		names[i] = t.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+// Translate implements Translator.
+func (c Chain) Translate(ctx context.Context, text, from, to string) ([]string, error) {
+	var errs []error
+	for _, t := range c {
+		out, err := t.Translate(ctx, text, from, to)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(out) > 0 {
+			return out, nil
+		}
+	}
+	if len(errs) == len(c) {
+		return nil, errors.Join(errs...)
+	}
+	return nil, nil
+}
+
+// CacheTTL returns how long suggestions are cached: TRANSLATE_CACHE_TTL
+// (a duration such as 720h; 0 caches forever), default 30 days.
+func CacheTTL() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("TRANSLATE_CACHE_TTL")); err == nil && d >= 0 {
+		return d
+	}
+	return 30 * 24 * time.Hour
+}
+
+// cacheable reports whether suggestions of t may be cached. Translators opt
+// out with a Cacheable() bool method; all others are cached.
+func cacheable(t Translator) bool {
+	c, ok := t.(interface{ Cacheable() bool })
+	return !ok || c.Cacheable()
+}
+
+// Suggest returns translation suggestions for text, serving them from the
+// cache when possible. cached reports whether they came from the cache.
+// A Chain is walked member by member so that each is cached under its own
+// name and uncacheable members are always asked.
+// Texts are cached per translator as typed, up to NFC and surrounding
+// whitespace: case can change a translation ("Arm" is poor, "arm" an arm),
+// so "Messer" and "messer" are separate entries. Empty results are not cached.
+func Suggest(ctx context.Context, t Translator, text, from, to string) (suggestions []string, cached bool, err error) {
+	if t == nil {
+		return nil, false, ErrDisabled
+	}
+	text = strings.TrimSpace(text)
+	if text == "" || from == "" || to == "" {
+		return nil, false, fmt.Errorf("text and languages are required")
+	}
+	members, ok := t.(Chain)
+	if !ok {
+		members = Chain{t}
+	}
+	var errs []error
+	for _, m := range members {
+		out, hit, err := suggest(ctx, m, text, from, to)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(out) > 0 {
+			return out, hit, nil
+		}
+	}
+	if len(members) > 0 && len(errs) == len(members) {
+		return nil, false, fmt.Errorf("translate: %w", errors.Join(errs...))
+	}
+	return []string{}, false, nil
+}
+
+// suggest asks one translator, through the cache if it is cacheable.
+func suggest(ctx context.Context, t Translator, text, from, to string) ([]string, bool, error) {
+	key := norm.NFC.String(text)
+	useCache := cacheable(t)
+	if useCache {
+		if out, ok := store.GetTranslationCache(t.Name(), from, to, key, CacheTTL()); ok {
+			return out, true, nil
+		}
+	}
+	out, err := t.Translate(ctx, text, from, to)
+	if err != nil {
+		return nil, false, err
+	}
+	out = cleanSuggestions(out)
+	if useCache && len(out) > 0 {
+		if err := store.SaveTranslationCache(t.Name(), from, to, key, out, CacheTTL()); err != nil {
+			log.Printf("cache translation of %q: %v", text, err)
+		}
+	}
+	return out, false, nil
+}
+
+// cleanSuggestions trims, drops empty and duplicate suggestions and caps
+// their number at MaxSuggestions.
+func cleanSuggestions(in []string) []string {
+	out := []string{}
+	seen := map[string]bool{}
+	for _, s := range in {
+		s = strings.TrimSpace(s)
+		if s == "" || seen[strings.ToLower(s)] {
+			continue
+		}
+		seen[strings.ToLower(s)] = true
+		out = append(out, s)
+		if len(out) == MaxSuggestions {
+			break
+		}
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+
+	"learnlang-backend/blob"
+)
+
+// ErrInvalidAudio means an upload is not in a supported audio format.
+var ErrInvalidAudio = errors.New("invalid audio")
+
+// SniffAudio detects the audio container from the first bytes of a file.
+// http.DetectContentType misses the WebM/MP4 containers browsers record to,
+// so the common pronunciation formats are matched here directly.
+func SniffAudio(head []byte) (string, bool) {
+	switch {
+	case bytes.HasPrefix(head, []byte("ID3")),
+		len(head) >= 2 && head[0] == 0xFF && head[1]&0xE0 == 0xE0 && head[1]&0x06 != 0:
+		// ID3 tag or bare MPEG frame sync with a valid layer (excludes ADTS AAC)
+		return "audio/mpeg", true
+	case len(head) >= 12 && bytes.Equal(head[:4], []byte("RIFF")) && bytes.Equal(head[8:12], []byte("WAVE")):
+		return "audio/wav", true
+	case bytes.HasPrefix(head, []byte("OggS")):
+		return "audio/ogg", true
+	case bytes.HasPrefix(head, []byte("fLaC")):
+		return "audio/flac", true
+	case bytes.HasPrefix(head, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		return "audio/webm", true
+	case len(head) >= 12 && bytes.Equal(head[4:8], []byte("ftyp")):
+		return "audio/mp4", true
+	}
+	return "", false
+}
+
+// audioExt maps a supported audio content type to a file extension.
+func audioExt(contentType string) (string, bool) {
+	switch contentType {
+	case "audio/mpeg":
+		return ".mp3", true
+	case "audio/wav":
+		return ".wav", true
+	case "audio/ogg":
+		return ".ogg", true
+	case "audio/flac":
+		return ".flac", true
+	case "audio/webm":
+		return ".webm", true
+	case "audio/mp4":
+		return ".m4a", true
+	}
+	return "", false
+}
+
+// UploadAudio saves an audio clip to the blob store under audio/<sha256>.<ext>.
+// The bytes are stored as uploaded; identical clips share one file.
+// Parameters mirror UploadImage.
+func UploadAudio(contentType string, head []byte, n int, rest io.Reader) (StoredFile, error) {
+	ext, ok := audioExt(contentType)
+	if !ok {
+		return StoredFile{}, ErrInvalidAudio
+	}
+	body, err := io.ReadAll(rest)
+	if err != nil {
+		return StoredFile{}, err
+	}
+	data := append(head[:n:n], body...)
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	f := StoredFile{
+		URL:         "/files/audio/" + hash + ext,
+		Hash:        hash,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+	}
+	if f.Created, err = putIfAbsent(blob.Default(), "audio/"+hash+ext, data, contentType); err != nil {
+		return StoredFile{}, err
+	}
+	return f, nil
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSniffAudio(t *testing.T) {
+	tests := []struct {
+		name string
+		head []byte
+		want string
+	}{
+		{"id3", []byte("ID3\x04\x00\x00"), "audio/mpeg"},
+		{"mpeg frame", []byte{0xFF, 0xFB, 0x90, 0x00}, "audio/mpeg"},
+		{"wav", []byte("RIFF\x24\x00\x00\x00WAVEfmt "), "audio/wav"},
+		{"ogg", []byte("OggS\x00\x02"), "audio/ogg"},
+		{"webm", []byte{0x1A, 0x45, 0xDF, 0xA3, 0x9F}, "audio/webm"},
+		{"m4a", []byte("\x00\x00\x00\x20ftypM4A "), "audio/mp4"},
+		{"html", []byte("<html><body>"), ""},
+		{"png", []byte("\x89PNG\r\n\x1a\n"), ""},
+		{"adts aac", []byte{0xFF, 0xF1, 0x50, 0x80}, ""},
+	}
+	for _, tt := range tests {
+		got, ok := SniffAudio(tt.head)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("%s: SniffAudio = %q, %v; want %q", tt.name, got, ok, tt.want)
+		}
+	}
+}
+
+func TestUploadAudio(t *testing.T) {
+	useUploadDir(t, t.TempDir())
+	data := []byte("ID3\x04\x00\x00 fake mp3 body")
+	first, err := UploadAudio("audio/mpeg", data, 6, bytes.NewReader(data[6:]))
+	if err != nil {
+		t.Fatalf("UploadAudio: %v", err)
+	}
+	if first.URL != "/files/audio/"+first.Hash+".mp3" || !first.Created || first.Size != int64(len(data)) {
+		t.Fatalf("unexpected stored file: %+v", first)
+	}
+	second, err := UploadAudio("audio/mpeg", data, len(data), bytes.NewReader(nil))
+	if err != nil || second.URL != first.URL || second.Created {
+		t.Fatalf("expected dedup: %+v, %v", second, err)
+	}
+	if _, err := UploadAudio("text/html", data, len(data), bytes.NewReader(nil)); !errors.Is(err, ErrInvalidAudio) {
+		t.Fatalf("expected ErrInvalidAudio, got %v", err)
+	}
+}
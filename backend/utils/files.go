@@ -1,12 +1,18 @@
 package utils
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
-	"path/filepath"
 	"strings"
+	"time"
+
+	"learnlang-backend/blob"
 )
 
 // UploadDir returns the directory used to store uploaded files.
@@ -19,108 +25,109 @@ func UploadDir() string {
 	return "" // unreachable
 }
 
-// UploadImage saves an uploaded image to disk and returns the public URL path (e.g., /files/name.ext).
+// StoredFile describes a blob saved by UploadImage or UploadAudio.
+type StoredFile struct {
+	URL         string // public URL path, e.g. /files/images/<sha256>.jpg
+	Hash        string // hex SHA-256 of the stored bytes
+	ContentType string
+	Size        int64
+	Created     bool // false if an identical blob was already stored
+}
+
+// UploadImage saves an uploaded image to the blob store under a content-addressed name.
+// The image is decoded and normalized with ProcessImage (orientation, metadata
+// stripping, downscaling) before it is hashed; identical results share one file.
+// Blob stores write atomically, so concurrent uploads never observe partial files.
 // Parameters:
-// - baseName: logical name to use for the filename base (will be sanitized)
-// - originalFilename: the original uploaded filename (used to derive extension if present)
-// - contentType: detected MIME type (used to derive extension if original missing)
+// - contentType: detected MIME type of the upload
 // - head: the first bytes already read from the file stream (used for content that was sniffed)
 // - n: number of valid bytes in head
-// - rest: an io.Reader for the remaining file content to write
-func UploadImage(baseName, originalFilename, contentType string, head []byte, n int, rest io.Reader) (string, error) {
-	// Ensure images subdirectory under the configured upload dir exists (do not create root here)
-	imagesDir := filepath.Join(UploadDir(), "images")
-	if err := os.MkdirAll(imagesDir, 0o755); err != nil {
-		return "", fmt.Errorf("prepare images dir: %w", err)
+// - rest: an io.Reader for the remaining file content
+func UploadImage(contentType string, head []byte, n int, rest io.Reader) (StoredFile, error) {
+	if _, ok := imageExt(contentType); !ok {
+		return StoredFile{}, fmt.Errorf("unknown file type")
 	}
-	// determine extension
-	ext := filepath.Ext(originalFilename)
-	if ext == "" {
-		switch contentType {
-		case "image/jpeg":
-			ext = ".jpg"
-		case "image/png":
-			ext = ".png"
-		case "image/webp":
-			ext = ".webp"
-		case "image/gif":
-			ext = ".gif"
-		default:
-			return "", fmt.Errorf("unknown file type")
-		}
+	body, err := io.ReadAll(rest)
+	if err != nil {
+		return StoredFile{}, err
 	}
-
-	base := sanitizeFileBase(baseName)
-	if base == "" {
-		base = "file"
+	original := append(head[:n:n], body...)
+	opts := ImageOptionsFromEnv()
+	processed, outType, err := ProcessImage(original, opts)
+	if err != nil {
+		return StoredFile{}, err
 	}
-	fname := base + ext
-	diskPath := filepath.Join(imagesDir, fname)
-	// avoid collisions
-	if _, err := os.Stat(diskPath); err == nil {
-		for i := 1; ; i++ {
-			candidate := fmt.Sprintf("%s-%d%s", base, i, ext)
-			candidatePath := filepath.Join(imagesDir, candidate)
-			if _, err := os.Stat(candidatePath); os.IsNotExist(err) {
-				fname = candidate
-				diskPath = candidatePath
-				break
-			}
-		}
+	ext, _ := imageExt(outType)
+	sum := sha256.Sum256(processed)
+	hash := hex.EncodeToString(sum[:])
+	fname := hash + ext
+	img := StoredFile{
+		URL:         "/files/images/" + fname,
+		Hash:        hash,
+		ContentType: outType,
+		Size:        int64(len(processed)),
 	}
 
-	dst, err := os.Create(diskPath)
-	if err != nil {
-		return "", fmt.Errorf("save file: %w", err)
+	if img.Created, err = putIfAbsent(blob.Default(), "images/"+fname, processed, outType); err != nil {
+		return StoredFile{}, err
 	}
-	defer dst.Close()
-
-	if n > 0 {
-		if _, err := dst.Write(head[:n]); err != nil {
-			return "", fmt.Errorf("write head: %w", err)
+	if opts.OriginalsDir != "" {
+		origExt, _ := imageExt(contentType)
+		// The directory itself stays private; originals may still carry EXIF.
+		if err := os.MkdirAll(opts.OriginalsDir, 0o700); err != nil {
+			return StoredFile{}, fmt.Errorf("prepare originals dir: %w", err)
+		}
+		if _, err := putIfAbsent(blob.NewLocal(opts.OriginalsDir), hash+origExt, original, contentType); err != nil {
+			return StoredFile{}, fmt.Errorf("save original: %w", err)
 		}
 	}
-	if _, err := io.Copy(dst, rest); err != nil {
-		return "", err
+	if img.Created {
+		// Variants are also generated on demand, so a failure here is not fatal.
+		if err := GenerateImageVariants(img.URL); err != nil && !errors.Is(err, ErrVariantUnsupported) {
+			log.Printf("generate variants for %s: %v", img.URL, err)
+		}
 	}
-	return "/files/images/" + fname, nil
+	return img, nil
 }
 
-// sanitizeFileBase converts a name into a safe filename base.
-func sanitizeFileBase(s string) string {
-	s = strings.TrimSpace(s)
-	s = strings.ToLower(s)
-	s = strings.ReplaceAll(s, " ", "-")
-	var b strings.Builder
-	b.Grow(len(s))
-	for _, r := range s {
-		switch {
-		case r >= 'a' && r <= 'z':
-			b.WriteRune(r)
-		case r >= '0' && r <= '9':
-			b.WriteRune(r)
-		case r == '-' || r == '_':
-			b.WriteRune(r)
-		}
+// putIfAbsent stores a content-addressed blob in store unless it already exists.
+func putIfAbsent(store blob.Store, key string, data []byte, contentType string) (created bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if _, err := store.Stat(ctx, key); err == nil {
+		return false, nil
+	} else if !errors.Is(err, blob.ErrNotFound) {
+		return false, fmt.Errorf("check file: %w", err)
 	}
-	out := b.String()
-	if out == "" {
-		return out
+	if err := store.Put(ctx, key, data, contentType); err != nil {
+		return false, fmt.Errorf("save file: %w", err)
 	}
-	var b2 strings.Builder
-	prevDash := false
-	for _, r := range out {
-		if r == '-' {
-			if !prevDash {
-				b2.WriteRune(r)
-			}
-			prevDash = true
-			continue
-		}
-		prevDash = false
-		b2.WriteRune(r)
+	return true, nil
+}
+
+// imageExt maps a supported image content type to a file extension.
+func imageExt(contentType string) (string, bool) {
+	switch contentType {
+	case "image/jpeg":
+		return ".jpg", true
+	case "image/png":
+		return ".png", true
+	case "image/webp":
+		return ".webp", true
+	case "image/gif":
+		return ".gif", true
+	}
+	return "", false
+}
+
+// UploadKey maps a public URL path (/files/...) to its blob store key.
+// Returns false for URLs outside /files/ or keys escaping the store root.
+func UploadKey(publicURL string) (string, bool) {
+	key, ok := strings.CutPrefix(publicURL, "/files/")
+	if !ok || !blob.ValidKey(key) {
+		return "", false
 	}
-	return b2.String()
+	return key, true
 }
 
 // VerifyUploadDirWritable checks that UPLOAD_DIR exists, is a directory, and is writable.
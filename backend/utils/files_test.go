@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"learnlang-backend/blob"
+)
+
+// useUploadDir points UPLOAD_DIR and the default blob store at dir.
+func useUploadDir(t *testing.T, dir string) {
+	t.Helper()
+	t.Setenv("UPLOAD_DIR", dir)
+	if err := blob.Init(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUploadKey(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+		ok   bool
+	}{
+		{"/files/images/cat.png", "images/cat.png", true},
+		{"/files/../etc/passwd", "", false},
+		{"/files/", "", false},
+		{"/other/cat.png", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := UploadKey(tt.url)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("UploadKey(%q) = %q, %v; want %q, %v", tt.url, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestUploadImage_ContentAddressedDedup(t *testing.T) {
+	dir := t.TempDir()
+	useUploadDir(t, dir)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 3, 3))); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	first, err := UploadImage("image/png", data, len(data), bytes.NewReader(nil))
+	if err != nil {
+		t.Fatalf("UploadImage: %v", err)
+	}
+	second, err := UploadImage("image/png", data, len(data), bytes.NewReader(nil))
+	if err != nil {
+		t.Fatalf("UploadImage: %v", err)
+	}
+	if first.URL != second.URL || !first.Created || second.Created {
+		t.Fatalf("expected dedup: first=%+v second=%+v", first, second)
+	}
+	if first.URL != "/files/images/"+first.Hash+".png" {
+		t.Fatalf("expected hash-based name, got %q", first.URL)
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, "images"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files int
+	for _, e := range entries {
+		if !e.IsDir() {
+			files++
+		}
+	}
+	if files != 1 {
+		t.Fatalf("expected 1 stored file, got %d", files)
+	}
+}
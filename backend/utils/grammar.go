@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"learnlang-backend/models"
+)
+
+// MaxGrammarFieldLen caps each grammatical attribute.
+const MaxGrammarFieldLen = 64
+
+// grammarRules describes the grammatical attributes a language accepts.
+type grammarRules struct {
+	genders     []string // canonical genders, in quiz order
+	articles    []string // definite article per gender, parallel to genders
+	verbs       bool     // principal parts and separable prefix
+	auxiliaries []string
+}
+
+// grammarByLang is keyed by languages.code. Languages missing here reject
+// grammatical attributes.
+var grammarByLang = map[string]grammarRules{
+	"de": {
+		genders:     []string{"masculine", "feminine", "neuter"},
+		articles:    []string{"der", "die", "das"},
+		verbs:       true,
+		auxiliaries: []string{"haben", "sein"},
+	},
+	"hi": {
+		genders: []string{"masculine", "feminine"},
+	},
+}
+
+// genderAliases maps common abbreviations to canonical genders.
+var genderAliases = map[string]string{
+	"m": "masculine", "masc": "masculine",
+	"f": "feminine", "fem": "feminine",
+	"n": "neuter", "neut": "neuter",
+}
+
+// NormalizeGrammar trims and validates v.Grammar against the rules of the
+// vocab's language (langCode as in languages.code). Genders may be given as
+// abbreviations or, where the language has them, definite articles
+// ("der" -> masculine). An empty Grammar is cleared to nil.
+func NormalizeGrammar(langCode string, v *models.Vocab) error {
+	g := v.Grammar
+	if g == nil {
+		return nil
+	}
+	for _, f := range []*string{&g.Gender, &g.Plural, &g.SeparablePrefix, &g.Present, &g.Preterite, &g.PastParticiple, &g.Auxiliary} {
+		*f = strings.TrimSpace(*f)
+		if utf8.RuneCountInString(*f) > MaxGrammarFieldLen {
+			return fmt.Errorf("grammar attribute %q too long; max %d characters", *f, MaxGrammarFieldLen)
+		}
+	}
+	if *g == (models.Grammar{}) {
+		v.Grammar = nil
+		return nil
+	}
+	rules, ok := grammarByLang[strings.ToLower(langCode)]
+	if !ok {
+		return fmt.Errorf("grammar attributes are not supported for language %q", langCode)
+	}
+
+	noun := g.Gender != "" || g.Plural != ""
+	verb := g.SeparablePrefix != "" || g.Present != "" || g.Preterite != "" || g.PastParticiple != "" || g.Auxiliary != ""
+	switch {
+	case noun && verb:
+		return fmt.Errorf("grammar mixes noun attributes (gender, plural) with verb attributes")
+	case noun && v.PartOfSpeech != "" && v.PartOfSpeech != "noun":
+		return fmt.Errorf("gender and plural apply to nouns, not %s", v.PartOfSpeech)
+	case verb && !rules.verbs:
+		return fmt.Errorf("verb attributes are not supported for language %q", langCode)
+	case verb && v.PartOfSpeech != "" && v.PartOfSpeech != "verb":
+		return fmt.Errorf("verb attributes apply to verbs, not %s", v.PartOfSpeech)
+	}
+
+	if g.Gender != "" {
+		gender, ok := canonicalGender(rules, g.Gender)
+		if !ok {
+			return fmt.Errorf("invalid gender %q; expected one of %s", g.Gender, strings.Join(rules.genders, ", "))
+		}
+		g.Gender = gender
+	}
+	if g.Auxiliary != "" {
+		g.Auxiliary = strings.ToLower(g.Auxiliary)
+		if !contains(rules.auxiliaries, g.Auxiliary) {
+			return fmt.Errorf("invalid auxiliary %q; expected one of %s", g.Auxiliary, strings.Join(rules.auxiliaries, ", "))
+		}
+	}
+	if g.SeparablePrefix != "" {
+		g.SeparablePrefix = strings.ToLower(g.SeparablePrefix)
+		name := strings.ToLower(strings.TrimSpace(v.Name))
+		if !strings.HasPrefix(name, g.SeparablePrefix) || len(name) == len(g.SeparablePrefix) {
+			return fmt.Errorf("separable prefix %q is not a prefix of %q", g.SeparablePrefix, v.Name)
+		}
+	}
+	return nil
+}
+
+// CanonicalGender maps a gender name, abbreviation or definite article
+// (e.g. "m", "die") to the language's canonical gender. It reports false for
+// languages without grammatical gender or genders they do not have.
+func CanonicalGender(langCode, s string) (string, bool) {
+	return canonicalGender(grammarByLang[strings.ToLower(langCode)], strings.TrimSpace(s))
+}
+
+func canonicalGender(rules grammarRules, s string) (string, bool) {
+	s = strings.ToLower(s)
+	if alias, ok := genderAliases[s]; ok {
+		s = alias
+	}
+	for i, a := range rules.articles {
+		if s == a {
+			s = rules.genders[i]
+		}
+	}
+	return s, contains(rules.genders, s)
+}
+
+// Articles returns the definite articles of a language in quiz order, or nil
+// if it has none.
+func Articles(langCode string) []string {
+	return grammarByLang[strings.ToLower(langCode)].articles
+}
+
+// DefiniteArticle returns the definite article for a noun's gender, e.g.
+// "das" for a German neuter noun.
+func DefiniteArticle(langCode string, g *models.Grammar) (string, bool) {
+	if g == nil || g.Gender == "" {
+		return "", false
+	}
+	rules := grammarByLang[strings.ToLower(langCode)]
+	for i, gender := range rules.genders {
+		if gender == g.Gender && i < len(rules.articles) {
+			return rules.articles[i], true
+		}
+	}
+	return "", false
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"testing"
+
+	"learnlang-backend/models"
+)
+
+func TestNormalizeGrammar(t *testing.T) {
+	v := models.Vocab{Name: "Messer", PartOfSpeech: "noun", Grammar: &models.Grammar{Gender: " das ", Plural: "Messer"}}
+	if err := NormalizeGrammar("de", &v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.Grammar.Gender != "neuter" {
+		t.Errorf("gender = %q; want neuter", v.Grammar.Gender)
+	}
+	if a, ok := DefiniteArticle("de", v.Grammar); !ok || a != "das" {
+		t.Errorf("DefiniteArticle = %q, %v; want das", a, ok)
+	}
+
+	verb := models.Vocab{Name: "anrufen", Grammar: &models.Grammar{SeparablePrefix: "An", Present: "ruft an", Preterite: "rief an", PastParticiple: "angerufen", Auxiliary: "haben"}}
+	if err := NormalizeGrammar("de", &verb); err != nil || verb.Grammar.SeparablePrefix != "an" {
+		t.Fatalf("verb: err=%v grammar=%+v", err, verb.Grammar)
+	}
+
+	empty := models.Vocab{Name: "x", Grammar: &models.Grammar{Plural: "  "}}
+	if err := NormalizeGrammar("en", &empty); err != nil || empty.Grammar != nil {
+		t.Fatalf("empty grammar should be cleared: err=%v grammar=%+v", err, empty.Grammar)
+	}
+}
+
+func TestNormalizeGrammar_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		lang string
+		v    models.Vocab
+	}{
+		{"unknown gender", "de", models.Vocab{Name: "Messer", Grammar: &models.Grammar{Gender: "common"}}},
+		{"hindi has no neuter", "hi", models.Vocab{Name: "चाकू", Grammar: &models.Grammar{Gender: "n"}}},
+		{"hindi verb attributes", "hi", models.Vocab{Name: "जाना", Grammar: &models.Grammar{PastParticiple: "गया"}}},
+		{"unsupported language", "fr", models.Vocab{Name: "couteau", Grammar: &models.Grammar{Gender: "m"}}},
+		{"gender on a verb", "de", models.Vocab{Name: "fahren", PartOfSpeech: "verb", Grammar: &models.Grammar{Gender: "m"}}},
+		{"mixed noun and verb", "de", models.Vocab{Name: "Essen", Grammar: &models.Grammar{Gender: "n", Preterite: "aß"}}},
+		{"bad auxiliary", "de", models.Vocab{Name: "fahren", Grammar: &models.Grammar{Auxiliary: "werden"}}},
+		{"prefix not in name", "de", models.Vocab{Name: "fahren", Grammar: &models.Grammar{SeparablePrefix: "ab"}}},
+	}
+	for _, tt := range tests {
+		if err := NormalizeGrammar(tt.lang, &tt.v); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestAnswerMatches(t *testing.T) {
+	tests := []struct {
+		answer, expected string
+		want             bool
+	}{
+		{" Der ", "der", true},
+		{"the  knife", "The knife", true},
+		{"das", "der", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		if got := AnswerMatches(tt.answer, tt.expected); got != tt.want {
+			t.Errorf("AnswerMatches(%q, %q) = %v; want %v", tt.answer, tt.expected, got, tt.want)
+		}
+	}
+}
@@ -1,9 +1,12 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5/middleware"
 )
@@ -77,3 +80,45 @@ func WriteErrorWithRequest(w http.ResponseWriter, r *http.Request, status int, c
 	log.Printf("Error [%s]: %s (RequestID: %s)", code, msg, GetRequestID(r))
 	WriteJSON(w, status, ErrorResponse{Error: msg, Code: code, RequestID: GetRequestID(r)})
 }
+
+// WriteOKDataCached writes a 200 OK success envelope with a strong ETag
+// derived from the body, answering 304 Not Modified when the request's
+// If-None-Match already holds it. Use for GET endpoints whose response is
+// deterministic for a given state.
+func WriteOKDataCached(w http.ResponseWriter, r *http.Request, data any, meta any) {
+	body, err := json.Marshal(SuccessResponse{Data: data, Meta: meta})
+	if err != nil {
+		WriteErrorWithRequest(w, r, http.StatusInternalServerError, CodeInternal, "failed to encode response")
+		return
+	}
+	body = append(body, '\n') // match json.Encoder output
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	h := w.Header()
+	h.Set("ETag", etag)
+	// Clients may cache but must revalidate; the data is mutable.
+	h.Set("Cache-Control", "private, no-cache")
+	if ETagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	h.Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// ETagMatches reports whether an If-None-Match header value matches etag,
+// using the weak comparison RFC 9110 requires for If-None-Match.
+func ETagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" || etag == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, cand := range strings.Split(ifNoneMatch, ",") {
+		cand = strings.TrimSpace(cand)
+		if cand == "*" || strings.TrimPrefix(cand, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
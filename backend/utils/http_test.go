@@ -114,3 +114,26 @@ func assertHTTPResponse[T any](t *testing.T, rr *httptest.ResponseRecorder, expe
 		t.Errorf("Expected body %+v, got %+v", expectedBody, actual)
 	}
 }
+
+func TestWriteOKDataCached(t *testing.T) {
+	rr := httptest.NewRecorder()
+	WriteOKDataCached(rr, httptest.NewRequest(http.MethodGet, "/api/packs/p1", nil), map[string]string{"id": "p1"}, nil)
+	etag := rr.Header().Get("ETag")
+	if rr.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with ETag, got %d %q", rr.Code, etag)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/packs/p1", nil)
+	req.Header.Set("If-None-Match", "W/"+etag)
+	rr = httptest.NewRecorder()
+	WriteOKDataCached(rr, req, map[string]string{"id": "p1"}, nil)
+	if rr.Code != http.StatusNotModified || rr.Body.Len() != 0 {
+		t.Fatalf("expected empty 304, got %d %q", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	WriteOKDataCached(rr, req, map[string]string{"id": "p2"}, nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("changed data must not match, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // register the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"os"
+	"strconv"
+)
+
+// ErrInvalidImage is returned when upload data cannot be decoded as an image.
+var ErrInvalidImage = errors.New("invalid image")
+
+// maxImagePixels guards against decompression bombs.
+const maxImagePixels = 50_000_000
+
+// ImageOptions controls how uploads are normalized before storage.
+type ImageOptions struct {
+	MaxDimension int    // longest side after downscaling; 0 disables resizing
+	JPEGQuality  int    // 1-100
+	OriginalsDir string // if set, the untouched upload is kept here (keep outside UPLOAD_DIR)
+}
+
+// ImageOptionsFromEnv reads IMAGE_MAX_DIMENSION (default 1600),
+// IMAGE_JPEG_QUALITY (default 85) and IMAGE_ORIGINALS_DIR (default unset).
+func ImageOptionsFromEnv() ImageOptions {
+	opts := ImageOptions{MaxDimension: 1600, JPEGQuality: 85, OriginalsDir: os.Getenv("IMAGE_ORIGINALS_DIR")}
+	if n, err := strconv.Atoi(os.Getenv("IMAGE_MAX_DIMENSION")); err == nil && n >= 0 {
+		opts.MaxDimension = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("IMAGE_JPEG_QUALITY")); err == nil && n >= 1 && n <= 100 {
+		opts.JPEGQuality = n
+	}
+	return opts
+}
+
+// ProcessImage decodes data, applies EXIF orientation, downscales it to
+// opts.MaxDimension and re-encodes it, which drops all metadata.
+// Returns the encoded bytes and their content type.
+//
+// JPEG stays JPEG; PNG and GIF become PNG. Animated GIFs keep only their first
+// frame. WebP cannot be decoded with the standard library and is rejected.
+func ProcessImage(data []byte, opts ImageOptions) ([]byte, string, error) {
+	if isWebP(data) {
+		return nil, "", fmt.Errorf("%w: webp is not supported", ErrInvalidImage)
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxImagePixels {
+		return nil, "", fmt.Errorf("%w: unsupported dimensions %dx%d", ErrInvalidImage, cfg.Width, cfg.Height)
+	}
+
+	// For GIFs this decodes the first frame only.
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if format == "jpeg" {
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+	img = downscale(img, opts.MaxDimension)
+
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		q := opts.JPEGQuality
+		if q == 0 {
+			q = 85
+		}
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: q}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/jpeg", nil
+	default:
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/png", nil
+	}
+}
+
+// downscale shrinks img so its longest side is at most maxDim.
+// Smaller images are returned unchanged.
+func downscale(img image.Image, maxDim int) image.Image {
+	b := img.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	if maxDim <= 0 || (sw <= maxDim && sh <= maxDim) {
+		return img
+	}
+	dw, dh := maxDim, sh*maxDim/sw
+	if sh > sw {
+		dw, dh = sw*maxDim/sh, maxDim
+	}
+	return resizeBox(img, dw, dh)
+}
+
+// downscaleToWidth shrinks img to the given width keeping its aspect ratio.
+// Narrower images are returned unchanged.
+func downscaleToWidth(img image.Image, width int) image.Image {
+	b := img.Bounds()
+	if width <= 0 || b.Dx() <= width {
+		return img
+	}
+	return resizeBox(img, width, b.Dy()*width/b.Dx())
+}
+
+// resizeBox scales img down to dw x dh by averaging source pixels (box filter).
+func resizeBox(img image.Image, dw, dh int) image.Image {
+	b := img.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	if dw < 1 {
+		dw = 1
+	}
+	if dh < 1 {
+		dh = 1
+	}
+	// Work on premultiplied RGBA so averaging respects transparency.
+	src, ok := img.(*image.RGBA)
+	if !ok || src.Bounds().Min != (image.Point{}) {
+		src = image.NewRGBA(image.Rect(0, 0, sw, sh))
+		draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for dy := 0; dy < dh; dy++ {
+		y0 := dy * sh / dh
+		y1 := max((dy+1)*sh/dh, y0+1)
+		for dx := 0; dx < dw; dx++ {
+			x0 := dx * sw / dw
+			x1 := max((dx+1)*sw/dw, x0+1)
+			var r, g, bl, a, n uint64
+			for y := y0; y < y1; y++ {
+				i := src.PixOffset(x0, y)
+				for x := x0; x < x1; x++ {
+					r += uint64(src.Pix[i])
+					g += uint64(src.Pix[i+1])
+					bl += uint64(src.Pix[i+2])
+					a += uint64(src.Pix[i+3])
+					n++
+					i += 4
+				}
+			}
+			o := dst.PixOffset(dx, dy)
+			dst.Pix[o] = uint8(r / n)
+			dst.Pix[o+1] = uint8(g / n)
+			dst.Pix[o+2] = uint8(bl / n)
+			dst.Pix[o+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+func isWebP(data []byte) bool {
+	return len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP"
+}
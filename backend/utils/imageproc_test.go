@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// exifOrientationSegment builds a minimal big-endian APP1 Exif segment.
+func exifOrientationSegment(orientation uint16) []byte {
+	tiff := []byte{'M', 'M', 0x00, 0x2A, 0, 0, 0, 8, 0, 1}
+	entry := make([]byte, 12)
+	binary.BigEndian.PutUint16(entry[0:2], 0x0112)
+	binary.BigEndian.PutUint16(entry[2:4], 3) // SHORT
+	binary.BigEndian.PutUint32(entry[4:8], 1)
+	binary.BigEndian.PutUint16(entry[8:10], orientation)
+	tiff = append(tiff, entry...)
+	tiff = append(tiff, 0, 0, 0, 0) // no next IFD
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	seg := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(seg[2:4], uint16(len(payload)+2))
+	return append(seg, payload...)
+}
+
+func TestProcessImage_RotatesAndStripsJPEG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 40, 20)), nil); err != nil {
+		t.Fatal(err)
+	}
+	raw := buf.Bytes()
+	withExif := append(append([]byte{0xFF, 0xD8}, exifOrientationSegment(6)...), raw[2:]...)
+	if got := jpegOrientation(withExif); got != 6 {
+		t.Fatalf("jpegOrientation = %d; want 6", got)
+	}
+
+	out, ct, err := ProcessImage(withExif, ImageOptions{MaxDimension: 1600, JPEGQuality: 80})
+	if err != nil {
+		t.Fatalf("ProcessImage: %v", err)
+	}
+	if ct != "image/jpeg" {
+		t.Fatalf("content type = %s; want image/jpeg", ct)
+	}
+	if bytes.Contains(out, []byte("Exif\x00\x00")) {
+		t.Fatalf("expected EXIF to be stripped")
+	}
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != 20 || cfg.Height != 40 {
+		t.Fatalf("size = %dx%d; want 20x40", cfg.Width, cfg.Height)
+	}
+}
+
+func TestProcessImage_Downscales(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 400, 200))); err != nil {
+		t.Fatal(err)
+	}
+	out, ct, err := ProcessImage(buf.Bytes(), ImageOptions{MaxDimension: 100})
+	if err != nil {
+		t.Fatalf("ProcessImage: %v", err)
+	}
+	cfg, err := png.DecodeConfig(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct != "image/png" || cfg.Width != 100 || cfg.Height != 50 {
+		t.Fatalf("got %s %dx%d; want image/png 100x50", ct, cfg.Width, cfg.Height)
+	}
+}
+
+func TestProcessImage_RejectsNonImage(t *testing.T) {
+	header := []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}
+	if _, _, err := ProcessImage(header, ImageOptions{}); !errors.Is(err, ErrInvalidImage) {
+		t.Fatalf("expected ErrInvalidImage, got %v", err)
+	}
+}
+
+func encodeGIF(t *testing.T, frames, w, h int) []byte {
+	t.Helper()
+	pal := color.Palette{color.Black, color.White}
+	g := &gif.GIF{}
+	for i := 0; i < frames; i++ {
+		g.Image = append(g.Image, image.NewPaletted(image.Rect(0, 0, w, h), pal))
+		g.Delay = append(g.Delay, 10)
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestProcessImage_AnimatedGIFBecomesStill(t *testing.T) {
+	out, ct, err := ProcessImage(encodeGIF(t, 3, 40, 20), ImageOptions{MaxDimension: 10})
+	if err != nil {
+		t.Fatalf("ProcessImage: %v", err)
+	}
+	cfg, err := png.DecodeConfig(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct != "image/png" || cfg.Width != 10 || cfg.Height != 5 {
+		t.Fatalf("got %s %dx%d; want image/png 10x5", ct, cfg.Width, cfg.Height)
+	}
+}
+
+func TestProcessImage_RejectsWebP(t *testing.T) {
+	data := []byte("RIFF\x0c\x00\x00\x00WEBPVP8 \x00\x00\x00\x00")
+	if _, _, err := ProcessImage(data, ImageOptions{}); !errors.Is(err, ErrInvalidImage) {
+		t.Fatalf("expected ErrInvalidImage, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+
+	"learnlang-backend/models"
+)
+
+// MaxLanguageNameLen caps Language.Name and Language.NativeName.
+const MaxLanguageNameLen = 64
+
+// Text directions of Language.Direction.
+const (
+	DirectionLTR = "ltr"
+	DirectionRTL = "rtl"
+)
+
+// rtlScripts are the ISO 15924 scripts written right to left.
+var rtlScripts = []string{"Adlm", "Arab", "Hebr", "Mand", "Nkoo", "Rohg", "Samr", "Syrc", "Thaa"}
+
+// NormalizeLanguage trims and validates l before it is stored. Code must be
+// an ISO 639-1 or 639-3 code and is canonicalised to its shortest form
+// ("deu" -> "de"). Blank fields are derived from the code: the English and
+// native names, the script (ISO 15924), the direction from the script and the
+// collation locale (BCP 47) from the code.
+func NormalizeLanguage(l *models.Language) error {
+	l.Code = strings.TrimSpace(l.Code)
+	base, err := language.ParseBase(l.Code)
+	if err != nil || base.String() == "und" {
+		return fmt.Errorf("invalid language code %q; expected ISO 639-1 or 639-3", l.Code)
+	}
+	l.Code = base.String()
+	tag := language.Make(l.Code)
+
+	l.Name = strings.TrimSpace(l.Name)
+	if l.Name == "" {
+		l.Name = display.English.Languages().Name(tag)
+	}
+	l.NativeName = strings.TrimSpace(l.NativeName)
+	if l.NativeName == "" {
+		l.NativeName = display.Self.Name(tag)
+	}
+	if l.Name == "" {
+		return fmt.Errorf("missing name for language %q", l.Code)
+	}
+	if utf8.RuneCountInString(l.Name) > MaxLanguageNameLen || utf8.RuneCountInString(l.NativeName) > MaxLanguageNameLen {
+		return fmt.Errorf("language name too long; max %d characters", MaxLanguageNameLen)
+	}
+
+	l.Script = strings.TrimSpace(l.Script)
+	if l.Script == "" {
+		if s, conf := tag.Script(); conf != language.No {
+			l.Script = s.String()
+		}
+	} else {
+		s, err := language.ParseScript(l.Script)
+		if err != nil {
+			return fmt.Errorf("invalid script %q; expected ISO 15924, e.g. Latn", l.Script)
+		}
+		l.Script = s.String()
+	}
+
+	l.Direction = strings.ToLower(strings.TrimSpace(l.Direction))
+	switch l.Direction {
+	case "":
+		l.Direction = DirectionLTR
+		if contains(rtlScripts, l.Script) {
+			l.Direction = DirectionRTL
+		}
+	case DirectionLTR, DirectionRTL:
+	default:
+		return fmt.Errorf("invalid direction %q; expected %s or %s", l.Direction, DirectionLTR, DirectionRTL)
+	}
+
+	l.Collation = strings.TrimSpace(l.Collation)
+	if l.Collation == "" {
+		l.Collation = l.Code
+	} else {
+		t, err := language.Parse(l.Collation)
+		if err != nil {
+			return fmt.Errorf("invalid collation locale %q; expected a BCP 47 tag, e.g. de-DE", l.Collation)
+		}
+		l.Collation = t.String()
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"testing"
+
+	"learnlang-backend/models"
+)
+
+func TestNormalizeLanguage(t *testing.T) {
+	l := models.Language{Code: " urd "}
+	if err := NormalizeLanguage(&l); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := models.Language{Code: "ur", Name: "Urdu", NativeName: "اردو", Script: "Arab", Direction: DirectionRTL, Collation: "ur"}
+	if l != want {
+		t.Errorf("got %+v; want %+v", l, want)
+	}
+
+	de := models.Language{Code: "de", Name: "German", Script: "latn", Direction: "LTR", Collation: "de-de"}
+	if err := NormalizeLanguage(&de); err != nil || de.Script != "Latn" || de.Direction != DirectionLTR || de.Collation != "de-DE" || de.NativeName != "Deutsch" {
+		t.Fatalf("de: err=%v language=%+v", err, de)
+	}
+}
+
+func TestNormalizeLanguage_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		l    models.Language
+	}{
+		{"missing code", models.Language{Name: "Hindi"}},
+		{"unknown code", models.Language{Code: "xx"}},
+		{"undetermined", models.Language{Code: "und"}},
+		{"full tag as code", models.Language{Code: "en-US"}},
+		{"bad script", models.Language{Code: "hi", Script: "Devanagari"}},
+		{"bad direction", models.Language{Code: "ar", Direction: "ttb"}},
+		{"bad collation", models.Language{Code: "de", Collation: "de_DE_phonebook!"}},
+	}
+	for _, tt := range tests {
+		if err := NormalizeLanguage(&tt.l); err == nil {
+			t.Errorf("%s: expected error, got %+v", tt.name, tt.l)
+		}
+	}
+}
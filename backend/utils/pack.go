@@ -3,20 +3,25 @@ package utils
 import (
 	"fmt"
 	"strings"
+
+	"learnlang-backend/textnorm"
 )
 
-func MakePackKey(userID, langID, packName string) string {
-	if userID == "" || langID == "" || packName == "" {
+// MakePackKey creates a composite key identifying a user's pack by name within
+// one language pair (target langID, translations in sourceLangID).
+func MakePackKey(userID, langID, sourceLangID, packName string) string {
+	if userID == "" || langID == "" || sourceLangID == "" || packName == "" {
 		return ""
 	}
-	return fmt.Sprintf("%s:%s:%s", strings.ToLower(userID), strings.ToLower(langID), strings.ToLower(packName))
+	return fmt.Sprintf("%s:%s:%s:%s", strings.ToLower(userID), strings.ToLower(langID), strings.ToLower(sourceLangID), strings.ToLower(packName))
 }
 
-// MakeVocabKey creates a composite key to uniquely identify a vocab entry
-// for a given user, language, pack and word text (case-insensitive).
-func MakeVocabKeyByPackID(packID, name string) string {
+// MakeVocabKeyByPackID creates a composite key to uniquely identify a vocab
+// entry in a pack. The name is folded with the rules of the pack's language
+// (langCode), so NFC/NFD, case and language-specific variants share a key.
+func MakeVocabKeyByPackID(packID, langCode, name string) string {
 	if packID == "" || name == "" {
 		return ""
 	}
-	return fmt.Sprintf("%s:%s", strings.ToLower(packID), strings.ToLower(name))
+	return fmt.Sprintf("%s:%s", strings.ToLower(packID), textnorm.Fold(langCode, name))
 }
@@ -4,20 +4,23 @@ import "testing"
 
 func TestMakePackKey(t *testing.T) {
 	tests := []struct {
-		userID   string
-		langID   string
-		packName string
-		expected string
+		userID       string
+		langID       string
+		sourceLangID string
+		packName     string
+		expected     string
 	}{
-		{"User1", "EN", "Starter", "user1:en:starter"},
-		{"ADMIN", "De", "Pro", "admin:de:pro"},
-		{"", "", "", ""},
+		{"User1", "EN", "DE", "Starter", "user1:en:de:starter"},
+		{"ADMIN", "De", "3", "Pro", "admin:de:3:pro"},
+		{"u1", "2", "3", "a:b", "u1:2:3:a:b"},
+		{"u1", "2", "", "Pro", ""},
+		{"", "", "", "", ""},
 	}
 
 	for _, tt := range tests {
-		result := MakePackKey(tt.userID, tt.langID, tt.packName)
+		result := MakePackKey(tt.userID, tt.langID, tt.sourceLangID, tt.packName)
 		if result != tt.expected {
-			t.Errorf("MakePackKey(%q, %q, %q) = %q; want %q", tt.userID, tt.langID, tt.packName, result, tt.expected)
+			t.Errorf("MakePackKey(%q, %q, %q, %q) = %q; want %q", tt.userID, tt.langID, tt.sourceLangID, tt.packName, result, tt.expected)
 		}
 	}
 }
@@ -0,0 +1,10 @@
+package utils
+
+import "strings"
+
+// AnswerMatches reports whether a quiz answer equals the expected one,
+// ignoring case and surrounding or repeated whitespace.
+func AnswerMatches(answer, expected string) bool {
+	norm := func(s string) string { return strings.ToLower(strings.Join(strings.Fields(s), " ")) }
+	return expected != "" && norm(answer) == norm(expected)
+}
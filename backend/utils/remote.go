@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Errors returned by FetchRemoteImage.
+var (
+	ErrBlockedAddress = errors.New("address not allowed")
+	ErrRemoteTooLarge = errors.New("remote file too large")
+	ErrRemoteNotImage = errors.New("remote file is not an image")
+)
+
+// nonPublicNets are ranges not covered by the net.IP helpers that must never
+// be fetched from (CGNAT, benchmarking, reserved, NAT64, etc.).
+var nonPublicNets = func() []*net.IPNet {
+	var out []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",
+		"100.64.0.0/10",
+		"192.0.0.0/24",
+		"192.0.2.0/24",
+		"198.18.0.0/15",
+		"198.51.100.0/24",
+		"203.0.113.0/24",
+		"240.0.0.0/4",
+		"64:ff9b::/96",
+		"2001:db8::/32",
+	} {
+		_, n, _ := net.ParseCIDR(cidr)
+		out = append(out, n)
+	}
+	return out
+}()
+
+// IsPublicIP reports whether ip is a globally routable unicast address.
+func IsPublicIP(ip net.IP) bool {
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, n := range nonPublicNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewPublicHTTPClient returns a client that refuses to connect to non-public
+// addresses. The check runs on the resolved IP at dial time, so it also covers
+// redirects and DNS rebinding.
+func NewPublicHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !IsPublicIP(net.ParseIP(host)) {
+				return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+			}
+			return nil
+		},
+	}
+	transport := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 3 {
+				return errors.New("too many redirects")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("%w: scheme %q", ErrBlockedAddress, req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+}
+
+// RemoteImage is an open remote image whose first bytes were already sniffed.
+type RemoteImage struct {
+	ContentType string
+	Head        []byte    // sniffed prefix of the body
+	Rest        io.Reader // remaining body, limited to the max size
+	body        io.Closer
+}
+
+// Close releases the underlying response body.
+func (ri *RemoteImage) Close() error { return ri.body.Close() }
+
+// FetchRemoteImage GETs an http(s) URL and verifies the response is an image of
+// at most maxSize bytes. The caller must Close the result.
+func FetchRemoteImage(ctx context.Context, client *http.Client, rawURL string, maxSize int64) (*RemoteImage, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: only absolute http(s) URLs are supported", ErrBlockedAddress)
+	}
+	if u.User != nil {
+		return nil, fmt.Errorf("%w: credentials in URL", ErrBlockedAddress)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "image/*")
+	req.Header.Set("User-Agent", "learnlang-backend/1.0")
+	resp, err := client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrBlockedAddress) {
+			return nil, ErrBlockedAddress
+		}
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if resp.ContentLength > maxSize {
+		resp.Body.Close()
+		return nil, ErrRemoteTooLarge
+	}
+	body := &maxBytesReader{r: resp.Body, remaining: maxSize}
+	head := make([]byte, 512)
+	n, err := io.ReadFull(body, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		resp.Body.Close()
+		if errors.Is(err, io.EOF) {
+			return nil, ErrRemoteNotImage
+		}
+		return nil, err
+	}
+	ct := http.DetectContentType(head[:n])
+	if !strings.HasPrefix(ct, "image/") {
+		resp.Body.Close()
+		return nil, ErrRemoteNotImage
+	}
+	return &RemoteImage{ContentType: ct, Head: head[:n], Rest: body, body: resp.Body}, nil
+}
+
+// maxBytesReader fails with ErrRemoteTooLarge once more than remaining bytes are read.
+type maxBytesReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (m *maxBytesReader) Read(p []byte) (int, error) {
+	if m.remaining < 0 {
+		return 0, ErrRemoteTooLarge
+	}
+	if int64(len(p)) > m.remaining+1 {
+		p = p[:m.remaining+1]
+	}
+	n, err := m.r.Read(p)
+	m.remaining -= int64(n)
+	if m.remaining < 0 {
+		return n, ErrRemoteTooLarge
+	}
+	return n, err
+}
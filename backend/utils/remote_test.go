@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"fd00::1", false},
+		{"fe80::1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := IsPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("IsPublicIP(%s) = %v; want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestFetchRemoteImage_BlocksLoopback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A})
+	}))
+	defer srv.Close()
+
+	_, err := FetchRemoteImage(context.Background(), NewPublicHTTPClient(2*time.Second), srv.URL, 1<<20)
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Fatalf("expected ErrBlockedAddress, got %v", err)
+	}
+}
+
+func TestFetchRemoteImage_Limits(t *testing.T) {
+	png := []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok.png":
+			_, _ = w.Write(png)
+		case "/big.png":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write(append(png, []byte(strings.Repeat("x", 2048))...))
+		default:
+			_, _ = w.Write([]byte("<html>not an image</html>"))
+		}
+	}))
+	defer srv.Close()
+	// The loopback test server is only reachable with an unguarded client.
+	client := srv.Client()
+
+	img, err := FetchRemoteImage(context.Background(), client, srv.URL+"/ok.png", 1024)
+	if err != nil {
+		t.Fatalf("ok.png: %v", err)
+	}
+	if img.ContentType != "image/png" {
+		t.Errorf("expected image/png, got %s", img.ContentType)
+	}
+	img.Close()
+
+	img, err = FetchRemoteImage(context.Background(), client, srv.URL+"/big.png", 1024)
+	if err == nil {
+		_, err = io.ReadAll(img.Rest)
+		img.Close()
+	}
+	if !errors.Is(err, ErrRemoteTooLarge) {
+		t.Errorf("big.png: expected ErrRemoteTooLarge, got %v", err)
+	}
+
+	if _, err := FetchRemoteImage(context.Background(), client, srv.URL+"/page", 1024); !errors.Is(err, ErrRemoteNotImage) {
+		t.Errorf("page: expected ErrRemoteNotImage, got %v", err)
+	}
+
+	if _, err := FetchRemoteImage(context.Background(), client, "file:///etc/passwd", 1024); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("file URL: expected ErrBlockedAddress, got %v", err)
+	}
+}
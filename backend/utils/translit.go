@@ -0,0 +1,221 @@
+package utils
+
+import (
+	"os"
+	"strings"
+	"unicode"
+
+	"learnlang-backend/models"
+)
+
+// MaxTransliterationLen caps Vocab.Transliteration.
+const MaxTransliterationLen = 200
+
+// Romanisation schemes supported by Transliterate.
+const (
+	SchemeIAST   = "iast"   // cākū, with diacritics
+	SchemeITRANS = "itrans" // chaakuu, ASCII only
+)
+
+// devanagari maps a Devanagari rune to its IAST and ITRANS spelling.
+type devanagari struct {
+	iast, itrans string
+}
+
+func (d devanagari) in(scheme string) string {
+	if scheme == SchemeITRANS {
+		return d.itrans
+	}
+	return d.iast
+}
+
+// Consonants carry an inherent "a" unless followed by a vowel sign or virama.
+var devConsonants = map[rune]devanagari{
+	'क': {"k", "k"}, 'ख': {"kh", "kh"}, 'ग': {"g", "g"}, 'घ': {"gh", "gh"}, 'ङ': {"ṅ", "~N"},
+	'च': {"c", "ch"}, 'छ': {"ch", "Ch"}, 'ज': {"j", "j"}, 'झ': {"jh", "jh"}, 'ञ': {"ñ", "~n"},
+	'ट': {"ṭ", "T"}, 'ठ': {"ṭh", "Th"}, 'ड': {"ḍ", "D"}, 'ढ': {"ḍh", "Dh"}, 'ण': {"ṇ", "N"},
+	'त': {"t", "t"}, 'थ': {"th", "th"}, 'द': {"d", "d"}, 'ध': {"dh", "dh"}, 'न': {"n", "n"},
+	'प': {"p", "p"}, 'फ': {"ph", "ph"}, 'ब': {"b", "b"}, 'भ': {"bh", "bh"}, 'म': {"m", "m"},
+	'य': {"y", "y"}, 'र': {"r", "r"}, 'ल': {"l", "l"}, 'व': {"v", "v"},
+	'श': {"ś", "sh"}, 'ष': {"ṣ", "Sh"}, 'स': {"s", "s"}, 'ह': {"h", "h"},
+	// Nukta consonants, precomposed (U+0958..U+095F)
+	'\u0958': {"q", "q"}, '\u0959': {"x", "K"}, '\u095A': {"ġ", "G"}, '\u095B': {"z", "z"},
+	'\u095C': {"ṛ", ".D"}, '\u095D': {"ṛh", ".Dh"}, '\u095E': {"f", "f"}, '\u095F': {"ẏ", "Y"},
+}
+
+// devNukta maps a consonant followed by the combining nukta (U+093C) to its
+// precomposed form.
+var devNukta = map[rune]rune{
+	'क': '\u0958', 'ख': '\u0959', 'ग': '\u095A', 'ज': '\u095B', 'ड': '\u095C', 'ढ': '\u095D', 'फ': '\u095E', 'य': '\u095F',
+}
+
+var devVowels = map[rune]devanagari{
+	'अ': {"a", "a"}, 'आ': {"ā", "aa"}, 'इ': {"i", "i"}, 'ई': {"ī", "ii"}, 'उ': {"u", "u"}, 'ऊ': {"ū", "uu"},
+	'ऋ': {"ṛ", "RRi"}, 'ए': {"e", "e"}, 'ऐ': {"ai", "ai"}, 'ओ': {"o", "o"}, 'औ': {"au", "au"}, 'ऑ': {"ô", "o"},
+}
+
+// devVowelSigns are the dependent forms (matras) written after a consonant.
+var devVowelSigns = map[rune]devanagari{
+	'ा': {"ā", "aa"}, 'ि': {"i", "i"}, 'ी': {"ī", "ii"}, 'ु': {"u", "u"}, 'ू': {"ū", "uu"},
+	'ृ': {"ṛ", "RRi"}, 'े': {"e", "e"}, 'ै': {"ai", "ai"}, 'ो': {"o", "o"}, 'ौ': {"au", "au"}, 'ॉ': {"ô", "o"},
+}
+
+var devMarks = map[rune]devanagari{
+	'ं': {"ṃ", "M"}, 'ँ': {"m̐", ".N"}, 'ः': {"ḥ", "H"}, '।': {".", "."}, '॥': {".", ".."}, 'ॐ': {"oṃ", "OM"},
+}
+
+const (
+	devVirama = '्'
+	devNuktaR = '़'
+)
+
+// Transliterate romanises Devanagari in s with the given scheme (SchemeIAST
+// or SchemeITRANS; anything else means IAST). Other characters pass through.
+// As in spoken Hindi, the inherent "a" of a word's last consonant is dropped
+// in words of more than one syllable (घर -> ghar, but न -> na).
+func Transliterate(s, scheme string) string {
+	var (
+		b         strings.Builder
+		runes     = []rune(s)
+		pendingA  bool // last consonant still carries its inherent a
+		syllables int  // syllables so far in the current word
+	)
+	flushA := func() {
+		if pendingA {
+			b.WriteString("a")
+			pendingA = false
+		}
+	}
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if i+1 < len(runes) && runes[i+1] == devNuktaR {
+			if nr, ok := devNukta[r]; ok {
+				r = nr
+				i++
+			}
+		}
+		switch {
+		case devConsonants[r] != (devanagari{}):
+			flushA()
+			b.WriteString(devConsonants[r].in(scheme))
+			pendingA = true
+			syllables++
+		case devVowelSigns[r] != (devanagari{}):
+			pendingA = false
+			b.WriteString(devVowelSigns[r].in(scheme))
+		case devVowels[r] != (devanagari{}):
+			flushA()
+			b.WriteString(devVowels[r].in(scheme))
+			syllables++
+		case r == devVirama:
+			pendingA = false
+			syllables--
+		case r == devNuktaR:
+			// stray nukta on a consonant without a precomposed form
+		case devMarks[r] != (devanagari{}):
+			flushA()
+			b.WriteString(devMarks[r].in(scheme))
+		case r >= '०' && r <= '९':
+			flushA()
+			b.WriteRune('0' + (r - '०'))
+		default:
+			// End of word: apply schwa deletion.
+			if pendingA && syllables <= 1 {
+				b.WriteString("a")
+			}
+			pendingA, syllables = false, 0
+			b.WriteRune(r)
+		}
+	}
+	if pendingA && syllables <= 1 {
+		b.WriteString("a")
+	}
+	return b.String()
+}
+
+// HasDevanagari reports whether s contains Devanagari letters.
+func HasDevanagari(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Devanagari, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// TransliterationScheme returns the scheme used for generated
+// transliterations: TRANSLIT_SCHEME if set to a supported value, else IAST.
+func TransliterationScheme() string {
+	if s := strings.ToLower(os.Getenv("TRANSLIT_SCHEME")); s == SchemeITRANS {
+		return s
+	}
+	return SchemeIAST
+}
+
+// TransliterationSource returns the vocab text a transliteration is made
+// from: whichever of name and translation is written in Devanagari.
+func TransliterationSource(v models.Vocab) string {
+	if HasDevanagari(v.Name) {
+		return v.Name
+	}
+	if HasDevanagari(v.Translation) {
+		return v.Translation
+	}
+	return ""
+}
+
+// AutoTransliteration generates a transliteration for v in a language that
+// has one (Hindi), or returns "".
+func AutoTransliteration(langCode string, v models.Vocab) string {
+	if strings.ToLower(langCode) != "hi" {
+		return ""
+	}
+	src := TransliterationSource(v)
+	if src == "" {
+		return ""
+	}
+	return Transliterate(src, TransliterationScheme())
+}
+
+// RomanizedMatches reports whether a romanised answer matches any of the
+// given romanisations, regardless of scheme: diacritics, case, ITRANS
+// punctuation and long-vowel doubling are ignored, so "chaaku", "caku" and
+// "cākū" all match.
+func RomanizedMatches(answer string, romanized ...string) bool {
+	key := romanKey(answer)
+	if key == "" {
+		return false
+	}
+	for _, r := range romanized {
+		if romanKey(r) == key {
+			return true
+		}
+	}
+	return false
+}
+
+var romanFold = strings.NewReplacer(
+	"ā", "a", "ī", "i", "ū", "u", "ṛ", "r", "ṝ", "r", "ḷ", "l", "ṅ", "n", "ñ", "n",
+	"ṭ", "t", "ḍ", "d", "ṇ", "n", "ś", "s", "ṣ", "s", "ṃ", "m", "ḥ", "h", "ġ", "g", "ẏ", "y", "ô", "o",
+)
+
+var romanCollapse = strings.NewReplacer(
+	"aa", "a", "ii", "i", "ee", "i", "uu", "u", "oo", "u",
+	"rri", "r", "sh", "s", "ch", "c", "w", "v",
+)
+
+func romanKey(s string) string {
+	s = romanFold.Replace(strings.ToLower(s))
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), " ") {
+				b.WriteByte(' ')
+			}
+		}
+	}
+	return romanCollapse.Replace(strings.TrimSpace(b.String()))
+}
@@ -0,0 +1,35 @@
+package utils
+
+import "testing"
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		in, scheme, want string
+	}{
+		{"चाकू", SchemeIAST, "cākū"},
+		{"चाकू", SchemeITRANS, "chaakuu"},
+		{"घर", SchemeIAST, "ghar"},        // final schwa dropped
+		{"न", SchemeIAST, "na"},           // kept in one-syllable words
+		{"नमस्ते", SchemeIAST, "namaste"}, // conjunct via virama
+		{"हिंदी", SchemeITRANS, "hiMdii"},
+		{"ज़रूर", SchemeIAST, "zarūr"}, // combining nukta
+		{"पेड़ और घर", SchemeIAST, "peṛ aur ghar"},
+		{"knife", SchemeIAST, "knife"},
+	}
+	for _, tt := range tests {
+		if got := Transliterate(tt.in, tt.scheme); got != tt.want {
+			t.Errorf("Transliterate(%q, %q) = %q; want %q", tt.in, tt.scheme, got, tt.want)
+		}
+	}
+}
+
+func TestRomanizedMatches(t *testing.T) {
+	for _, answer := range []string{"cākū", "chaakuu", "Chaku", "caku"} {
+		if !RomanizedMatches(answer, "cākū") {
+			t.Errorf("RomanizedMatches(%q, cākū) = false; want true", answer)
+		}
+	}
+	if RomanizedMatches("kaanta", "cākū") || RomanizedMatches("", "") {
+		t.Error("unexpected match")
+	}
+}
@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"learnlang-backend/blob"
+)
+
+// ErrVariantUnsupported means no resized variant can be produced for an image
+// (e.g. a WebP or GIF stored before uploads were converted); clients should use
+// the full image instead.
+var ErrVariantUnsupported = errors.New("image variant unsupported")
+
+// ImageVariantWidths are the generated widths by variant name. "full" is the
+// stored upload itself.
+var ImageVariantWidths = map[string]int{
+	"thumb": 160,
+	"card":  480,
+}
+
+// ImageVariantURL returns the public URL of the width variant of an uploaded image.
+// Variants live under /files/images/w<width>/ next to the full image.
+func ImageVariantURL(publicURL string, width int) string {
+	name, ok := strings.CutPrefix(publicURL, "/files/images/")
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return publicURL
+	}
+	return "/files/images/w" + strconv.Itoa(width) + "/" + name
+}
+
+// ImageVariants returns a srcset-style map of variant name to URL, including "full".
+func ImageVariants(publicURL string) map[string]string {
+	if publicURL == "" {
+		return nil
+	}
+	out := map[string]string{"full": publicURL}
+	for name, w := range ImageVariantWidths {
+		out[name] = ImageVariantURL(publicURL, w)
+	}
+	return out
+}
+
+// IsImageVariantWidth reports whether width is one of the generated widths.
+func IsImageVariantWidth(width int) bool {
+	for _, w := range ImageVariantWidths {
+		if w == width {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateImageVariants writes every width variant for an uploaded image.
+func GenerateImageVariants(publicURL string) error {
+	name, ok := strings.CutPrefix(publicURL, "/files/images/")
+	if !ok {
+		return fmt.Errorf("not an image upload: %q", publicURL)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for _, w := range ImageVariantWidths {
+		if _, err := EnsureImageVariant(ctx, name, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnsureImageVariant returns the blob key of the width variant of images/<name>,
+// generating and caching it first if needed. Returns blob.ErrNotFound for
+// unknown names or widths.
+func EnsureImageVariant(ctx context.Context, name string, width int) (string, error) {
+	if name == "" || strings.ContainsAny(name, "/\\") || strings.HasPrefix(name, ".") || !IsImageVariantWidth(width) {
+		return "", blob.ErrNotFound
+	}
+	store := blob.Default()
+	key := variantKey(name, width)
+	if _, err := store.Stat(ctx, key); err == nil {
+		return key, nil
+	} else if !errors.Is(err, blob.ErrNotFound) {
+		return "", err
+	}
+
+	rc, _, err := store.Get(ctx, "images/"+name)
+	if err != nil {
+		return "", err
+	}
+	data, err := io.ReadAll(io.LimitReader(rc, 64<<20))
+	rc.Close()
+	if err != nil {
+		return "", err
+	}
+	out, ct, err := resizeEncoded(data, width)
+	if err != nil {
+		return "", err
+	}
+	// Concurrent requests may race here; both write identical bytes atomically.
+	if err := store.Put(ctx, key, out, ct); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+func variantKey(name string, width int) string {
+	return "images/w" + strconv.Itoa(width) + "/" + name
+}
+
+// resizeEncoded scales an already-processed JPEG or PNG to width and
+// re-encodes it in the same format.
+func resizeEncoded(data []byte, width int) ([]byte, string, error) {
+	if isWebP(data) {
+		return nil, "", ErrVariantUnsupported
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxImagePixels {
+		return nil, "", fmt.Errorf("%w: unsupported dimensions %dx%d", ErrInvalidImage, cfg.Width, cfg.Height)
+	}
+	if format == "gif" {
+		// uploads are converted to PNG; only older GIFs are stored as-is
+		return nil, "", ErrVariantUnsupported
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	img = downscaleToWidth(img, width)
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: ImageOptionsFromEnv().JPEGQuality}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/jpeg", nil
+	case "png":
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/png", nil
+	default:
+		return nil, "", ErrVariantUnsupported
+	}
+}
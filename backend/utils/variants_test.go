@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"learnlang-backend/blob"
+)
+
+func TestUploadImage_GeneratesVariants(t *testing.T) {
+	dir := t.TempDir()
+	useUploadDir(t, dir)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 800, 400))); err != nil {
+		t.Fatal(err)
+	}
+	img, err := UploadImage("image/png", buf.Bytes(), buf.Len(), bytes.NewReader(nil))
+	if err != nil {
+		t.Fatalf("UploadImage: %v", err)
+	}
+	url := img.URL
+	name := filepath.Base(url)
+	variants := ImageVariants(url)
+	if variants["full"] != url || variants["thumb"] != "/files/images/w160/"+name {
+		t.Fatalf("unexpected variants: %v", variants)
+	}
+	for name, w := range ImageVariantWidths {
+		key, ok := UploadKey(variants[name])
+		if !ok {
+			t.Fatalf("bad variant url %q", variants[name])
+		}
+		f, err := os.Open(filepath.Join(dir, filepath.FromSlash(key)))
+		if err != nil {
+			t.Fatalf("variant %s missing: %v", name, err)
+		}
+		cfg, err := png.DecodeConfig(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Width != w || cfg.Height != w/2 {
+			t.Errorf("variant %s = %dx%d; want %dx%d", name, cfg.Width, cfg.Height, w, w/2)
+		}
+	}
+
+}
+
+func TestEnsureImageVariant_RejectsUnknownWidthAndTraversal(t *testing.T) {
+	useUploadDir(t, t.TempDir())
+	ctx := context.Background()
+	if _, err := EnsureImageVariant(ctx, "cat.png", 123); !errors.Is(err, blob.ErrNotFound) {
+		t.Errorf("expected not-exist for unknown width, got %v", err)
+	}
+	if _, err := EnsureImageVariant(ctx, "../cat.png", 160); !errors.Is(err, blob.ErrNotFound) {
+		t.Errorf("expected not-exist for traversal, got %v", err)
+	}
+}
+
+func TestResizeEncoded_RejectsOversizedImage(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	// Claim 10000x10000 in the IHDR chunk and fix up its CRC.
+	data := buf.Bytes()
+	binary.BigEndian.PutUint32(data[16:20], 10000)
+	binary.BigEndian.PutUint32(data[20:24], 10000)
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(data[12:29]))
+	_, _, err := resizeEncoded(data, 160)
+	if !errors.Is(err, ErrInvalidImage) || !strings.Contains(err.Error(), "dimensions") {
+		t.Fatalf("expected dimensions rejected before decoding, got %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"learnlang-backend/models"
+)
+
+// PartsOfSpeech are the accepted values of Vocab.PartOfSpeech.
+var PartsOfSpeech = []string{
+	"noun", "verb", "adjective", "adverb", "pronoun", "preposition",
+	"postposition", "conjunction", "interjection", "numeral", "article",
+	"particle", "phrase",
+}
+
+// Limits for the optional vocab details.
+const (
+	MaxVocabExamples = 10
+	MaxExampleLen    = 500
+	MaxNotesLen      = 2000
+	MaxVocabTags     = 20
+	MaxTagLen        = 32
+
+	MaxVocabTranslations = 10
+	MaxVocabSynonyms     = 10
+	MaxTranslationLen    = 200
+
+	MaxVocabDifficulty = 5 // Vocab.Difficulty ranges 1..5; 0 is unrated
+)
+
+// NormalizeVocabDetails trims and validates the optional detail fields of v
+// in place. Tags are lowercased and de-duplicated; part of speech is lowercased.
+// Translations are de-duplicated with the primary Translation first (taken
+// from the list if unset); synonyms are de-duplicated and exclude Name.
+func NormalizeVocabDetails(v *models.Vocab) error {
+	v.Translation = strings.TrimSpace(v.Translation)
+	translations, err := uniqueTrimmed(append([]string{v.Translation}, v.Translations...), MaxTranslationLen, "translation")
+	if err != nil {
+		return err
+	}
+	if len(translations) > MaxVocabTranslations {
+		return fmt.Errorf("too many translations; max %d", MaxVocabTranslations)
+	}
+	if len(translations) > 0 {
+		v.Translation = translations[0]
+	}
+	v.Translations = translations
+
+	synonyms, err := uniqueTrimmed(append([]string{v.Name}, v.Synonyms...), MaxTranslationLen, "synonym")
+	if err != nil {
+		return err
+	}
+	if len(synonyms) > 0 && strings.EqualFold(synonyms[0], strings.TrimSpace(v.Name)) {
+		synonyms = synonyms[1:]
+	}
+	if len(synonyms) > MaxVocabSynonyms {
+		return fmt.Errorf("too many synonyms; max %d", MaxVocabSynonyms)
+	}
+	v.Synonyms = synonyms
+
+	v.Notes = strings.TrimSpace(v.Notes)
+	if utf8.RuneCountInString(v.Notes) > MaxNotesLen {
+		return fmt.Errorf("notes too long; max %d characters", MaxNotesLen)
+	}
+
+	v.Transliteration = strings.TrimSpace(v.Transliteration)
+	if utf8.RuneCountInString(v.Transliteration) > MaxTransliterationLen {
+		return fmt.Errorf("transliteration too long; max %d characters", MaxTransliterationLen)
+	}
+
+	if v.Difficulty < 0 || v.Difficulty > MaxVocabDifficulty {
+		return fmt.Errorf("invalid difficulty %d; expected 1 to %d, or 0 for unrated", v.Difficulty, MaxVocabDifficulty)
+	}
+
+	v.PartOfSpeech = strings.ToLower(strings.TrimSpace(v.PartOfSpeech))
+	if v.PartOfSpeech != "" && !isPartOfSpeech(v.PartOfSpeech) {
+		return fmt.Errorf("invalid part_of_speech %q; expected one of %s", v.PartOfSpeech, strings.Join(PartsOfSpeech, ", "))
+	}
+
+	if len(v.Examples) > MaxVocabExamples {
+		return fmt.Errorf("too many examples; max %d", MaxVocabExamples)
+	}
+	examples := make([]models.Example, 0, len(v.Examples))
+	for i, e := range v.Examples {
+		e.Text = strings.TrimSpace(e.Text)
+		e.Translation = strings.TrimSpace(e.Translation)
+		if e.Text == "" {
+			return fmt.Errorf("example %d: text is required", i)
+		}
+		if utf8.RuneCountInString(e.Text) > MaxExampleLen || utf8.RuneCountInString(e.Translation) > MaxExampleLen {
+			return fmt.Errorf("example %d too long; max %d characters", i, MaxExampleLen)
+		}
+		examples = append(examples, e)
+	}
+	v.Examples = examples
+
+	seen := map[string]bool{}
+	tags := make([]string, 0, len(v.Tags))
+	for _, t := range v.Tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		if utf8.RuneCountInString(t) > MaxTagLen {
+			return fmt.Errorf("tag %q too long; max %d characters", t, MaxTagLen)
+		}
+		seen[t] = true
+		tags = append(tags, t)
+	}
+	if len(tags) > MaxVocabTags {
+		return fmt.Errorf("too many tags; max %d", MaxVocabTags)
+	}
+	v.Tags = tags
+	return nil
+}
+
+// uniqueTrimmed trims the items, drops empty ones and case-insensitive
+// duplicates, keeping first occurrences in order.
+func uniqueTrimmed(items []string, maxLen int, what string) ([]string, error) {
+	seen := map[string]bool{}
+	out := []string{}
+	for _, it := range items {
+		it = strings.TrimSpace(it)
+		key := strings.ToLower(it)
+		if it == "" || seen[key] {
+			continue
+		}
+		if utf8.RuneCountInString(it) > maxLen {
+			return nil, fmt.Errorf("%s %q too long; max %d characters", what, it, maxLen)
+		}
+		seen[key] = true
+		out = append(out, it)
+	}
+	return out, nil
+}
+
+func isPartOfSpeech(s string) bool {
+	for _, p := range PartsOfSpeech {
+		if p == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"learnlang-backend/models"
+)
+
+func TestNormalizeVocabDetails(t *testing.T) {
+	v := models.Vocab{
+		Notes:        "  informal  ",
+		PartOfSpeech: " Noun ",
+		Tags:         []string{"Food", " food", "", "kitchen"},
+		Examples:     []models.Example{{Text: " Das Messer ist scharf. ", Translation: " The knife is sharp. "}},
+	}
+	if err := NormalizeVocabDetails(&v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.Notes != "informal" || v.PartOfSpeech != "noun" {
+		t.Errorf("got notes=%q pos=%q", v.Notes, v.PartOfSpeech)
+	}
+	if strings.Join(v.Tags, ",") != "food,kitchen" {
+		t.Errorf("tags = %v; want [food kitchen]", v.Tags)
+	}
+	if v.Examples[0].Text != "Das Messer ist scharf." || v.Examples[0].Translation != "The knife is sharp." {
+		t.Errorf("examples = %+v", v.Examples)
+	}
+}
+
+func TestNormalizeVocabDetails_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		v    models.Vocab
+	}{
+		{"unknown part of speech", models.Vocab{PartOfSpeech: "gerundive"}},
+		{"example without text", models.Vocab{Examples: []models.Example{{Translation: "hi"}}}},
+		{"long tag", models.Vocab{Tags: []string{strings.Repeat("x", MaxTagLen+1)}}},
+		{"long notes", models.Vocab{Notes: strings.Repeat("x", MaxNotesLen+1)}},
+		{"too many examples", models.Vocab{Examples: make([]models.Example, MaxVocabExamples+1)}},
+		{"difficulty too high", models.Vocab{Difficulty: MaxVocabDifficulty + 1}},
+		{"negative difficulty", models.Vocab{Difficulty: -1}},
+	}
+	for _, tt := range tests {
+		if err := NormalizeVocabDetails(&tt.v); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestNormalizeVocabDetails_Translations(t *testing.T) {
+	v := models.Vocab{Name: "Auto", Translation: " car ", Translations: []string{"automobile", "Car", ""}, Synonyms: []string{"auto", "Wagen", "wagen"}}
+	if err := NormalizeVocabDetails(&v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.Translation != "car" || strings.Join(v.Translations, ",") != "car,automobile" {
+		t.Errorf("translation=%q translations=%v; want car [car automobile]", v.Translation, v.Translations)
+	}
+	if strings.Join(v.Synonyms, ",") != "Wagen" {
+		t.Errorf("synonyms = %v; want [Wagen]", v.Synonyms)
+	}
+
+	// Without a primary the first listed translation becomes primary.
+	v = models.Vocab{Name: "Auto", Translations: []string{"automobile", "car"}}
+	if err := NormalizeVocabDetails(&v); err != nil || v.Translation != "automobile" {
+		t.Errorf("primary = %q, err = %v; want automobile", v.Translation, err)
+	}
+}
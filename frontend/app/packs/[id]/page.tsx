@@ -1,15 +1,28 @@
-import { getPackDetail } from "@/lib/api";
+import { getPackDetail, type VocabSort } from "@/lib/api";
 import AddVocabForm from "@/components/AddVocabForm";
 import PackVocabViewerClient from "@/components/PackVocabViewerClient";
 import Link from "next/link";
 
 export const dynamic = "force-dynamic";
 
-type Props = { params: Promise<{ id: string }> };
+type Props = {
+  params: Promise<{ id: string }>;
+  searchParams: Promise<{ sort?: string; order?: string }>;
+};
 
-export default async function PackDetailPage({ params }: Props) {
+const sorts: { key: VocabSort; label: string }[] = [
+  { key: "name", label: "Name" },
+  { key: "translation", label: "Translation" },
+  { key: "created", label: "Added" },
+  { key: "difficulty", label: "Difficulty" },
+];
+
+export default async function PackDetailPage({ params, searchParams }: Props) {
   const { id } = await params;
-  const detail = await getPackDetail(id);
+  const { sort, order } = await searchParams;
+  const key = sorts.find((s) => s.key === sort)?.key ?? "name";
+  const desc = order === "desc";
+  const detail = await getPackDetail(id, { key, desc });
 
   return (
     <main className="p-6 max-w-5xl mx-auto">
@@ -17,8 +30,31 @@ export default async function PackDetailPage({ params }: Props) {
         <Link href="/packs" className="text-blue-600 hover:underline">← Back</Link>
         <h1 className="text-2xl font-semibold">{detail.pack.name}</h1>
       </div>
-  <AddVocabForm packId={detail.pack.id} />
-  <PackVocabViewerClient vocabs={detail.vocabs} />
+  <AddVocabForm
+    packId={detail.pack.id}
+    langId={detail.pack.lang_id}
+    sourceLangId={detail.pack.source_lang_id}
+    langCode={detail.language?.code}
+    sourceLangCode={detail.source_language?.code}
+  />
+  <nav className="my-3 flex gap-3 text-sm">
+    <span className="text-gray-600">Sort by</span>
+    {sorts.map((s) => (
+      <Link
+        key={s.key}
+        href={`?sort=${s.key}${s.key === key && !desc ? "&order=desc" : ""}`}
+        className={s.key === key ? "font-semibold" : "text-blue-600 hover:underline"}
+      >
+        {s.label}
+        {s.key === key ? (desc ? " ↓" : " ↑") : ""}
+      </Link>
+    ))}
+  </nav>
+  <PackVocabViewerClient
+    vocabs={detail.vocabs}
+    language={detail.language}
+    sourceLanguage={detail.source_language}
+  />
     </main>
   );
 }
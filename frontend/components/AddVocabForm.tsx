@@ -2,12 +2,17 @@
 
 import { useCallback, useState } from "react";
 import { useRouter } from "next/navigation";
+import { lookupDictionary, suggestTranslation } from "@/lib/api";
 
 type Props = {
   packId: string;
+  langId?: string; // language of names, for dictionary lookups
+  sourceLangId?: string;
+  langCode?: string; // language of names
+  sourceLangCode?: string; // language of translations
 };
 
-export default function AddVocabForm({ packId }: Props) {
+export default function AddVocabForm({ packId, langId, sourceLangId, langCode, sourceLangCode }: Props) {
   const router = useRouter();
   const [name, setName] = useState("");
   const [translation, setTranslation] = useState("");
@@ -16,6 +21,41 @@ export default function AddVocabForm({ packId }: Props) {
   const [dragging, setDragging] = useState(false);
   const [submitting, setSubmitting] = useState(false);
   const [error, setError] = useState<string | null>(null);
+  const [suggestions, setSuggestions] = useState<string[]>([]);
+  const [partOfSpeech, setPartOfSpeech] = useState("");
+  const [gender, setGender] = useState("");
+
+  const onSuggest = async () => {
+    if (!name.trim() || !langCode || !sourceLangCode) return;
+    setError(null);
+    try {
+      const s = await suggestTranslation(name.trim(), langCode, sourceLangCode);
+      setSuggestions(s);
+      if (s.length > 0 && !translation.trim()) setTranslation(s[0]);
+    } catch (err: unknown) {
+      setError(err instanceof Error ? err.message : "Could not fetch suggestions");
+    }
+  };
+
+  // Prefills translation, part of speech and gender from the exact (or
+  // closest) dictionary match.
+  const onLookup = async () => {
+    if (!name.trim() || !langId) return;
+    setError(null);
+    try {
+      const [entry] = await lookupDictionary(langId, name.trim(), sourceLangId);
+      if (!entry) {
+        setError("No dictionary entry found");
+        return;
+      }
+      setSuggestions(entry.definitions);
+      if (!translation.trim() && entry.definitions.length > 0) setTranslation(entry.definitions[0]);
+      setPartOfSpeech(entry.part_of_speech ?? "");
+      setGender(entry.gender ?? "");
+    } catch (err: unknown) {
+      setError(err instanceof Error ? err.message : "Could not look up word");
+    }
+  };
 
   const onSubmit = async (e: React.FormEvent) => {
     e.preventDefault();
@@ -49,6 +89,8 @@ export default function AddVocabForm({ packId }: Props) {
   fd.append("translation", translation.trim());
       fd.append("pack_id", packId);
       fd.append("image", uploadFile);
+      if (partOfSpeech) fd.append("part_of_speech", partOfSpeech);
+      if (gender) fd.append("grammar", JSON.stringify({ gender }));
       const res = await fetch(`${base.replace(/\/$/, "")}/api/vocabs`, {
         method: "POST",
         body: fd,
@@ -60,6 +102,8 @@ export default function AddVocabForm({ packId }: Props) {
       // Reset form, refresh data
       setName("");
       setTranslation("");
+      setPartOfSpeech("");
+      setGender("");
       setFile(null);
       setImageUrl("");
       router.refresh();
@@ -145,8 +189,39 @@ export default function AddVocabForm({ packId }: Props) {
             value={translation}
             onChange={(e) => setTranslation(e.target.value)}
             placeholder="e.g. चाकू"
+            list={`suggestions-${packId}`}
             required
           />
+          <datalist id={`suggestions-${packId}`}>
+            {suggestions.map((s) => (
+              <option key={s} value={s} />
+            ))}
+          </datalist>
+          {langCode && sourceLangCode && (
+            <button
+              type="button"
+              onClick={onSuggest}
+              disabled={!name.trim()}
+              className="self-start text-sm text-blue-600 hover:underline disabled:text-gray-400"
+            >
+              Suggest translation
+            </button>
+          )}
+          {langId && (
+            <button
+              type="button"
+              onClick={onLookup}
+              disabled={!name.trim()}
+              className="self-start text-sm text-blue-600 hover:underline disabled:text-gray-400"
+            >
+              Look up in dictionary
+            </button>
+          )}
+          {(partOfSpeech || gender) && (
+            <span className="text-xs text-gray-600">
+              {[partOfSpeech, gender].filter(Boolean).join(", ")}
+            </span>
+          )}
         </label>
         <div className="flex flex-col gap-1">
           <span className="text-sm text-gray-700">Image</span>
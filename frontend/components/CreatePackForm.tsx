@@ -12,6 +12,7 @@ export default function CreatePackForm({ languages }: Props) {
 	const router = useRouter();
 	const [name, setName] = useState("");
 	const [lang, setLang] = useState(languages[0]?.id ?? "");
+	const [sourceLang, setSourceLang] = useState(languages.find((l) => l.code === "en")?.id ?? "");
 	const [userId, setUserId] = useState("u1");
 	const [error, setError] = useState<string | null>(null);
 	const [busy, setBusy] = useState(false);
@@ -24,9 +25,18 @@ export default function CreatePackForm({ languages }: Props) {
 			setError("Name and language are required");
 			return;
 		}
+		if (sourceLang === lang) {
+			setError("Translation language must differ from the language being learned");
+			return;
+		}
 		setBusy(true);
 		try {
-			await createPack({ name: name.trim(), lang_id: lang, user_id: userId.trim() || "u1" });
+			await createPack({
+				name: name.trim(),
+				lang_id: lang,
+				source_lang_id: sourceLang || undefined,
+				user_id: userId.trim() || "u1",
+			});
 			setName("");
 			router.refresh();
 		} catch (err: unknown) {
@@ -44,7 +54,7 @@ export default function CreatePackForm({ languages }: Props) {
 					No languages found. Please seed languages in the backend to enable pack creation.
 				</p>
 			)}
-			<div className="grid grid-cols-1 sm:grid-cols-3 gap-3 items-end">
+			<div className="grid grid-cols-1 sm:grid-cols-4 gap-3 items-end">
 				<label className="flex flex-col gap-1">
 					<span className="text-sm text-gray-700">Name</span>
 					<input
@@ -67,7 +77,23 @@ export default function CreatePackForm({ languages }: Props) {
 					>
 						{languages.map((l) => (
 							<option key={l.id} value={l.id}>
-								{l.name}
+								{l.native_name && l.native_name !== l.name ? `${l.name} (${l.native_name})` : l.name}
+							</option>
+						))}
+					</select>
+				</label>
+				<label className="flex flex-col gap-1">
+					<span className="text-sm text-gray-700">Translations in</span>
+					<select
+						className="border rounded px-2 py-1"
+						value={sourceLang}
+						onChange={(e) => setSourceLang(e.target.value)}
+						disabled={noLang}
+					>
+						<option value="">English (default)</option>
+						{languages.map((l) => (
+							<option key={l.id} value={l.id}>
+								{l.native_name && l.native_name !== l.name ? `${l.name} (${l.native_name})` : l.name}
 							</option>
 						))}
 					</select>
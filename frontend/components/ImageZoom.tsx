@@ -1,6 +1,7 @@
 "use client";
 
 import { useEffect, useState } from "react";
+import type { Language } from "@/lib/api";
 
 type Props = {
   src: string;
@@ -8,9 +9,19 @@ type Props = {
   thumbSize?: number; // px
   primaryLabel?: string; // shown immediately when zoomed (e.g., name)
   secondaryLabel?: string; // shown after click (e.g., translation)
+  primaryLang?: Pick<Language, "code" | "direction">;
+  secondaryLang?: Pick<Language, "code" | "direction">;
 };
 
-export default function ImageZoom({ src, alt = "", thumbSize = 80, primaryLabel, secondaryLabel }: Props) {
+export default function ImageZoom({
+  src,
+  alt = "",
+  thumbSize = 80,
+  primaryLabel,
+  secondaryLabel,
+  primaryLang,
+  secondaryLang,
+}: Props) {
   const [zoomed, setZoomed] = useState(false);
   const [revealed, setRevealed] = useState(false);
 
@@ -28,6 +39,11 @@ export default function ImageZoom({ src, alt = "", thumbSize = 80, primaryLabel,
     }
   };
 
+  const label =
+    revealed && secondaryLabel
+      ? { text: secondaryLabel, lang: secondaryLang }
+      : { text: primaryLabel, lang: primaryLang };
+
   // Close on ESC when zoomed
   useEffect(() => {
     if (!zoomed) return;
@@ -88,8 +104,13 @@ export default function ImageZoom({ src, alt = "", thumbSize = 80, primaryLabel,
             />
             {(primaryLabel || (revealed && secondaryLabel)) && (
               <div className="absolute inset-0 flex items-center justify-center">
-                <span className="px-4 py-2 bg-black/70 text-white text-xl font-semibold rounded">
-                  {revealed && secondaryLabel ? secondaryLabel : primaryLabel}
+                {/* lang/dir let the browser pick fonts and lay out RTL scripts (Arabic, Urdu) */}
+                <span
+                  lang={label.lang?.code}
+                  dir={label.lang?.direction ?? "auto"}
+                  className="px-4 py-2 bg-black/70 text-white text-xl font-semibold rounded"
+                >
+                  {label.text}
                 </span>
               </div>
             )}
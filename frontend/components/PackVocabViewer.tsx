@@ -1,11 +1,16 @@
 "use client";
 
 import ImageZoom from "@/components/ImageZoom";
-import { toImageUrl, type Vocab } from "@/lib/api";
+import { toImageUrl, type Language, type Vocab } from "@/lib/api";
 
-type Props = { vocabs: Vocab[] | null | undefined; onEdit?: (v: Vocab) => void };
+type Props = {
+  vocabs: Vocab[] | null | undefined;
+  language?: Language; // language of the names
+  sourceLanguage?: Language; // language of the translations
+  onEdit?: (v: Vocab) => void;
+};
 
-export default function PackVocabViewer({ vocabs, onEdit }: Props) {
+export default function PackVocabViewer({ vocabs, language, sourceLanguage, onEdit }: Props) {
   const list = Array.isArray(vocabs) ? vocabs : [];
   if (list.length === 0) {
     return <p className="text-gray-500">No vocabs in this pack.</p>;
@@ -22,7 +27,9 @@ export default function PackVocabViewer({ vocabs, onEdit }: Props) {
               alt={v.name}
               thumbSize={112}
               primaryLabel={v.name}
+              primaryLang={language}
               secondaryLabel={v.translation}
+              secondaryLang={sourceLanguage}
             />
             <button
               type="button"
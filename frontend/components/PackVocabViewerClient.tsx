@@ -2,14 +2,14 @@
 
 import { useState } from "react";
 import { useRouter } from "next/navigation";
-import type { Vocab } from "@/lib/api";
+import type { Language, Vocab } from "@/lib/api";
 import { updateVocab } from "@/lib/api";
 import PackVocabViewer from "@/components/PackVocabViewer";
 import EditVocabModal from "@/components/EditVocabModal";
 
-type Props = { vocabs: Vocab[] | null | undefined };
+type Props = { vocabs: Vocab[] | null | undefined; language?: Language; sourceLanguage?: Language };
 
-export default function PackVocabViewerClient({ vocabs }: Props) {
+export default function PackVocabViewerClient({ vocabs, language, sourceLanguage }: Props) {
   const router = useRouter();
   const [editing, setEditing] = useState<Vocab | null>(null);
 
@@ -22,7 +22,12 @@ export default function PackVocabViewerClient({ vocabs }: Props) {
 
   return (
     <>
-      <PackVocabViewer vocabs={vocabs} onEdit={(v) => setEditing(v)} />
+      <PackVocabViewer
+        vocabs={vocabs}
+        language={language}
+        sourceLanguage={sourceLanguage}
+        onEdit={(v) => setEditing(v)}
+      />
       <EditVocabModal vocab={editing} onClose={() => setEditing(null)} onSave={handleSave} />
     </>
   );
@@ -2,6 +2,7 @@ export type Pack = {
   id: string;
   name: string;
   lang_id: string;
+  source_lang_id?: string; // language of the translations
   user_id: string;
   public?: boolean;
 };
@@ -12,10 +13,16 @@ export type Vocab = {
   name: string;
   translation?: string;
   pack_id: string;
+  difficulty?: number; // 1 (easy) to 5 (hard); absent when unrated
+  created_at?: string;
 };
 
+export type VocabSort = "name" | "translation" | "created" | "difficulty";
+
 export type PackDetail = {
   pack: Pack;
+  language?: Language;
+  source_language?: Language;
   vocabs: Vocab[];
 };
 
@@ -23,6 +30,11 @@ export type Language = {
   id: string;
   name: string;
   code: string;
+  native_name?: string;
+  script?: string;
+  direction?: "ltr" | "rtl";
+  collation?: string;
+  enabled?: boolean;
 };
 
 function getBaseUrl() {
@@ -53,8 +65,12 @@ export async function getPacks(): Promise<Pack[]> {
   return [];
 }
 
-export async function getPackDetail(id: string): Promise<PackDetail> {
-  const res = await fetch(`${getBaseUrl()}/api/packs/${id}`, {
+export async function getPackDetail(
+  id: string,
+  sort?: { key: VocabSort; desc?: boolean }
+): Promise<PackDetail> {
+  const qs = sort ? `?sort=${sort.key}${sort.desc ? "&order=desc" : ""}` : "";
+  const res = await fetch(`${getBaseUrl()}/api/packs/${id}${qs}`, {
     next: { revalidate: 0 },
   });
   if (!res.ok) throw new Error(`Failed to fetch pack ${id}: ${res.status}`);
@@ -97,7 +113,46 @@ export async function getLanguages(): Promise<Language[]> {
   return [];
 }
 
-export async function createPack(input: { name: string; lang_id: string; user_id: string }): Promise<Pack> {
+// Suggests translations of text; from/to are language IDs or codes.
+export async function suggestTranslation(text: string, from: string, to: string): Promise<string[]> {
+  const qs = new URLSearchParams({ text, from, to });
+  const res = await fetch(`${getBaseUrl()}/api/suggest/translation?${qs}`);
+  if (!res.ok) throw new Error(`Failed to fetch suggestions: ${res.status}`);
+  const raw = (await res.json()) as ApiEnvelope<{ suggestions: string[] }>;
+  return raw.data?.suggestions ?? [];
+}
+
+export type DictionaryEntry = {
+  id: number;
+  lang_id: string;
+  source_lang_id: string;
+  headword: string;
+  part_of_speech?: string;
+  gender?: string;
+  definitions: string[];
+};
+
+// Looks up headwords starting with q in the imported offline dictionary;
+// exact matches come first.
+export async function lookupDictionary(
+  langId: string,
+  q: string,
+  sourceLangId?: string
+): Promise<DictionaryEntry[]> {
+  const qs = new URLSearchParams({ lang_id: langId, q });
+  if (sourceLangId) qs.set("source_lang_id", sourceLangId);
+  const res = await fetch(`${getBaseUrl()}/api/dictionary?${qs}`);
+  if (!res.ok) throw new Error(`Failed to look up word: ${res.status}`);
+  const raw = (await res.json()) as ApiEnvelope<DictionaryEntry[]>;
+  return raw.data ?? [];
+}
+
+export async function createPack(input: {
+  name: string;
+  lang_id: string;
+  source_lang_id?: string;
+  user_id: string;
+}): Promise<Pack> {
   const res = await fetch(`${getBaseUrl()}/api/packs`, {
     method: "POST",
     headers: { "Content-Type": "application/json" },